@@ -10,8 +10,16 @@ import (
 	"syscall"
 
 	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/cache"
+	"github.com/tusharr/go-ai-huggingface/internal/chat"
 	"github.com/tusharr/go-ai-huggingface/internal/config"
 	"github.com/tusharr/go-ai-huggingface/internal/handler"
+	"github.com/tusharr/go-ai-huggingface/internal/jobs"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
 	"github.com/tusharr/go-ai-huggingface/pkg/logger"
 )
 
@@ -41,12 +49,47 @@ func main() {
 		"model":     cfg.HuggingFace.DefaultModel,
 	})
 
+	// Initialize metrics. Model labels are bounded to the configured models.
+	knownModels := cfg.KnownModels()
+	appMetrics := metrics.New()
+	appMetrics.LimitModels(knownModels)
+
 	// Initialize services
-	aiService := ai.NewHuggingFaceService(&cfg.HuggingFace, appLogger)
-	aiHandler := handler.NewAIHandler(aiService, appLogger)
+	tokens := tokenizer.NewCounter(cfg.Models.TokenizerDir, appLogger)
+	hfService := ai.NewHuggingFaceService(&cfg.HuggingFace, tokens, appMetrics, appLogger)
+	hfService.LimitModels(knownModels)
+	aiService := newAIService(cfg, hfService, tokens, appMetrics, appLogger)
+	chatRenderer, err := chat.NewRenderer(&cfg.Chat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid chat template configuration: %v\n", err)
+		os.Exit(1)
+	}
+	jobManager := jobs.NewManager(aiService, jobs.NewMemoryStore(cfg.Jobs.Retention), &cfg.Jobs, appLogger)
+	aiHandler := handler.NewAIHandler(aiService, appMetrics, appLogger,
+		handler.WithChatRenderer(chatRenderer),
+		handler.WithAllowedOrigins(cfg.Auth.AllowedOrigins),
+		handler.WithMaxBodyBytes(cfg.Server.MaxBodyBytes),
+		handler.WithBatchConfig(&cfg.Batch),
+		handler.WithJobs(jobManager),
+		handler.WithModels(&cfg.Models),
+		handler.WithTaskModels(&cfg.HuggingFace),
+		handler.WithTokenizer(tokens),
+		handler.WithCircuitBreakers(hfService.CircuitBreakers),
+		handler.WithErrorDetails(cfg.Server.ErrorDetails),
+	)
+
+	// Initialize API key authentication
+	var keyStore auth.Store
+	if cfg.Auth.Enabled {
+		keyStore, err = auth.NewFileStore(cfg.Auth.KeysFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize API key store: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Setup routes
-	mux := setupRoutes(aiHandler)
+	mux := setupRoutes(cfg, aiHandler, keyStore)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -94,23 +137,108 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Let queued and running jobs finish
+	if err := jobManager.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error(ctx, "Jobs did not finish before shutdown", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	appLogger.Info(ctx, "Server exited properly", nil)
 }
 
-// setupRoutes configures all HTTP routes and middleware
-func setupRoutes(aiHandler *handler.AIHandler) http.Handler {
+// newAIService registers every configured provider behind a model router and
+// applies the configured decorators
+func newAIService(cfg *config.Config, hfService *ai.HuggingFaceService, tokens *tokenizer.Counter, appMetrics *metrics.Metrics, appLogger logger.Logger) model.AIService {
+	router := ai.NewRouter(&cfg.Providers, appLogger)
+	router.Register(config.ProviderHuggingFace, hfService)
+
+	if cfg.Providers.TGI.BaseURL != "" {
+		router.Register(config.ProviderTGI, ai.NewTGIService(&cfg.Providers.TGI, tokens, appMetrics, appLogger))
+	}
+	if cfg.Providers.OpenAI.BaseURL != "" {
+		router.Register(config.ProviderOpenAI, ai.NewOpenAIService(&cfg.Providers.OpenAI, tokens, appMetrics, appLogger))
+	}
+
+	// The cache sits below the fallback chains so that responses are cached
+	// under the model that served them, and below the registry defaults so
+	// that its keys hold them
+	var service model.AIService = router
+	if cfg.Cache.Enabled {
+		service = ai.NewCachedService(service, cache.NewLRU(cfg.Cache.MaxEntries), cfg.Cache.TTL, appMetrics, appLogger)
+	}
+	service = ai.NewDefaultsService(service, &cfg.Models, &cfg.HuggingFace, tokens)
+
+	return ai.NewFallbackService(service, &cfg.Fallbacks, &cfg.HuggingFace, appMetrics, appLogger)
+}
+
+// setupRoutes configures all HTTP routes and middleware. API routes require
+// authentication when keyStore is non-nil.
+func setupRoutes(cfg *config.Config, aiHandler *handler.AIHandler, keyStore auth.Store) http.Handler {
 	mux := http.NewServeMux()
 
+	// handle registers a route with per-route metrics
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, aiHandler.Instrument(pattern, h))
+	}
+
+	var authenticate func(http.Handler) http.Handler
+	if keyStore != nil {
+		authenticate = aiHandler.Authenticate(keyStore, auth.NewQuotas())
+	}
+
+	// handleAPI registers a /v1 route, which requires an API key when
+	// authentication is enabled
+	handleAPI := func(pattern string, h http.Handler) {
+		if authenticate != nil {
+			h = authenticate(h)
+		}
+		mux.Handle(pattern, aiHandler.Instrument(pattern, h.ServeHTTP))
+	}
+
+	// handleAI registers an inference route, which is additionally subject
+	// to the token rate limit
+	var tokenLimiter func(http.Handler) http.Handler
+	if cfg.HuggingFace.RateLimitTPM > 0 {
+		tokenLimiter = aiHandler.TokenRateLimiter(ratelimit.NewLimiter(cfg.HuggingFace.RateLimitTPM))
+	}
+	handleAI := func(pattern string, h http.HandlerFunc) {
+		var next http.Handler = h
+		if tokenLimiter != nil {
+			next = tokenLimiter(next)
+		}
+		handleAPI(pattern, next)
+	}
+
 	// Health and monitoring endpoints
-	mux.HandleFunc("/health", aiHandler.Health)
+	handle("/health", aiHandler.Health)
 	mux.HandleFunc("/metrics", aiHandler.Metrics)
 
 	// AI endpoints
-	mux.HandleFunc("/v1/text/generate", aiHandler.GenerateText)
-	mux.HandleFunc("/v1/text/complete", aiHandler.GenerateCompletion)
-	mux.HandleFunc("/v1/text/sentiment", aiHandler.AnalyzeSentiment)
-	mux.HandleFunc("/v1/text/summarize", aiHandler.SummarizeText)
-	mux.HandleFunc("/v1/models/validate", aiHandler.ValidateModel)
+	handleAI("/v1/text/generate", aiHandler.GenerateText)
+	handleAI("/v1/text/generate/batch", aiHandler.GenerateBatch)
+	handleAI("/v1/text/complete", aiHandler.GenerateCompletion)
+	handleAI("/v1/text/sentiment", aiHandler.AnalyzeSentiment)
+	handleAI("/v1/text/summarize", aiHandler.SummarizeText)
+	handleAI("/v1/text/chat", aiHandler.Chat)
+	handleAI("/v1/text/classify", aiHandler.ClassifyText)
+	handleAI("/v1/text/entities", aiHandler.ExtractEntities)
+	handleAI("/v1/text/translate", aiHandler.TranslateText)
+	handleAI("/v1/text/qa", aiHandler.AnswerQuestion)
+
+	// Models
+	handleAPI("GET /v1/models", http.HandlerFunc(aiHandler.ListModels))
+	handleAPI("GET /v1/models/validate", http.HandlerFunc(aiHandler.ValidateModel))
+	handleAPI("GET /v1/models/{id...}", http.HandlerFunc(aiHandler.GetModel))
+
+	// Asynchronous jobs
+	handleAI("POST /v1/jobs", aiHandler.CreateJob)
+	handleAPI("GET /v1/jobs/{id}", http.HandlerFunc(aiHandler.GetJob))
+
+	// OpenAI-compatible endpoints
+	handleAI("/v1/chat/completions", aiHandler.ChatCompletions)
+	handleAI("/v1/completions", aiHandler.Completions)
+	handleAI("/v1/embeddings", aiHandler.Embeddings)
 
 	// API documentation endpoint
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -127,10 +255,23 @@ func setupRoutes(aiHandler *handler.AIHandler) http.Handler {
 				"health":            "GET /health",
 				"metrics":           "GET /metrics",
 				"generate_text":     "POST /v1/text/generate",
+				"generate_batch":    "POST /v1/text/generate/batch",
 				"complete_text":     "POST /v1/text/complete",
 				"analyze_sentiment": "POST /v1/text/sentiment",
 				"summarize_text":    "POST /v1/text/summarize",
+				"chat":              "POST /v1/text/chat",
+				"classify_text":     "POST /v1/text/classify",
+				"extract_entities":  "POST /v1/text/entities",
+				"translate_text":    "POST /v1/text/translate",
+				"answer_question":   "POST /v1/text/qa",
+				"list_models":       "GET /v1/models",
+				"get_model":         "GET /v1/models/{id}",
 				"validate_model":    "GET /v1/models/validate?model=<model_name>",
+				"chat_completions":  "POST /v1/chat/completions",
+				"completions":       "POST /v1/completions",
+				"embeddings":        "POST /v1/embeddings",
+				"create_job":        "POST /v1/jobs",
+				"get_job":           "GET /v1/jobs/{id}",
 			},
 			"documentation": "https://github.com/tusharr/go-ai-huggingface",
 		}
@@ -144,9 +285,15 @@ func setupRoutes(aiHandler *handler.AIHandler) http.Handler {
 	// Apply middleware (order matters!)
 	var handler http.Handler = mux
 
+	// Apply response cache control
+	handler = aiHandler.CacheControl(handler)
+
 	// Apply CORS middleware
 	handler = aiHandler.EnableCORS(handler)
 
+	// Apply the request body limit
+	handler = aiHandler.LimitBody(handler)
+
 	// Apply rate limiting from configuration
 	handler = aiHandler.RateLimiter(cfg.HuggingFace.RateLimitRPM)(handler)
 
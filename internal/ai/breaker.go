@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// breakerState is the state of a circuit breaker. The values are exported as
+// the circuit_breaker_state metric.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerHalfOpen:
+		return "half_open"
+	case breakerOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+// breakerResult is the outcome of a request allowed by a circuit breaker
+type breakerResult int
+
+const (
+	breakerSuccess   breakerResult = iota // the model answered, even with a client error
+	breakerFailure                        // the model failed or could not be reached
+	breakerAbandoned                      // the caller gave up; says nothing about the model
+)
+
+// circuitBreakers holds a circuit breaker per model. A breaker opens when the
+// share of failed requests in a window reaches the configured ratio. While
+// open, requests fail fast; once the open timeout has passed a single probe
+// request is let through, which closes the breaker on success and opens it
+// again on failure. A probe that never reports its outcome is replaced by
+// another after the open timeout, so that the breaker cannot stay half-open.
+type circuitBreakers struct {
+	config  config.CircuitBreakerConfig
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	covered func(modelName string) bool // models with a breaker; nil for every model
+
+	mu     sync.Mutex
+	models map[string]*circuitBreaker
+}
+
+// circuitBreaker is the breaker of a single model
+type circuitBreaker struct {
+	state       breakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool      // a half-open probe is in flight
+	probeStart  time.Time // when the probe was let through
+}
+
+func newCircuitBreakers(cfg config.CircuitBreakerConfig, metrics *metrics.Metrics) *circuitBreakers {
+	return &circuitBreakers{
+		config:  cfg,
+		metrics: metrics,
+		now:     time.Now,
+		models:  make(map[string]*circuitBreaker),
+	}
+}
+
+// enabled reports whether breakers are configured
+func (b *circuitBreakers) enabled() bool {
+	return b.config.ErrorRatio > 0
+}
+
+// covers reports whether the model has a breaker
+func (b *circuitBreakers) covers(modelName string) bool {
+	return b.covered == nil || b.covered(modelName)
+}
+
+// allow reports whether a request to the model may be sent, returning a
+// circuit_open error when it may not. Allowed requests must report their
+// outcome with record on every path, abandoned ones included.
+func (b *circuitBreakers) allow(modelName string) error {
+	if !b.enabled() || !b.covers(modelName) {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.breaker(modelName)
+	switch cb.state {
+	case breakerOpen:
+		if wait := b.config.OpenTimeout - b.now().Sub(cb.openedAt); wait > 0 {
+			return b.reject(modelName, wait)
+		}
+		b.transition(modelName, cb, breakerHalfOpen)
+		cb.probing, cb.probeStart = true, b.now()
+	case breakerHalfOpen:
+		if cb.probing && b.now().Sub(cb.probeStart) < b.config.OpenTimeout {
+			return b.reject(modelName, 0)
+		}
+		cb.probing, cb.probeStart = true, b.now()
+	}
+	return nil
+}
+
+// record reports the outcome of a request let through by allow
+func (b *circuitBreakers) record(modelName string, result breakerResult) {
+	if !b.enabled() || !b.covers(modelName) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.breaker(modelName)
+	switch cb.state {
+	case breakerOpen:
+		// A request sent before the breaker opened
+		return
+	case breakerHalfOpen:
+		cb.probing = false
+		switch result {
+		case breakerSuccess:
+			b.transition(modelName, cb, breakerClosed)
+		case breakerFailure:
+			b.transition(modelName, cb, breakerOpen)
+		}
+		return
+	}
+
+	now := b.now()
+	if now.Sub(cb.windowStart) >= b.config.Window {
+		cb.windowStart, cb.requests, cb.failures = now, 0, 0
+	}
+	if result == breakerAbandoned {
+		return
+	}
+	cb.requests++
+	if result == breakerFailure {
+		cb.failures++
+	}
+	if cb.requests >= b.config.MinRequests && float32(cb.failures) >= b.config.ErrorRatio*float32(cb.requests) {
+		b.transition(modelName, cb, breakerOpen)
+	}
+}
+
+// statuses returns the state of every breaker, ordered by model
+func (b *circuitBreakers) statuses() []model.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]model.CircuitBreakerStatus, 0, len(b.models))
+	for name, cb := range b.models {
+		status := model.CircuitBreakerStatus{
+			Model:    name,
+			State:    cb.state.String(),
+			Requests: cb.requests,
+			Failures: cb.failures,
+		}
+		if cb.state != breakerClosed {
+			openedAt := cb.openedAt
+			status.OpenedAt = &openedAt
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Model < statuses[j].Model })
+	return statuses
+}
+
+// breaker returns the breaker of the model, creating it closed. Callers must
+// hold b.mu.
+func (b *circuitBreakers) breaker(modelName string) *circuitBreaker {
+	cb, ok := b.models[modelName]
+	if !ok {
+		cb = &circuitBreaker{windowStart: b.now()}
+		b.models[modelName] = cb
+	}
+	return cb
+}
+
+// transition moves a breaker to a new state. Callers must hold b.mu.
+func (b *circuitBreakers) transition(modelName string, cb *circuitBreaker, state breakerState) {
+	now := b.now()
+	switch state {
+	case breakerOpen:
+		cb.openedAt = now
+	case breakerClosed:
+		cb.windowStart, cb.requests, cb.failures = now, 0, 0
+	}
+	cb.state = state
+	b.metrics.SetCircuitBreakerState(modelName, int(state))
+}
+
+// reject builds the error returned while a breaker is open. Callers must hold
+// b.mu.
+func (b *circuitBreakers) reject(modelName string, wait time.Duration) error {
+	b.metrics.IncCircuitBreakerRejected(modelName)
+	details := "a probe request is in progress"
+	if wait > 0 {
+		details = fmt.Sprintf("retry in %ds", int((wait+time.Second-1)/time.Second))
+	}
+	return &model.ErrorResponse{
+		Code:    http.StatusServiceUnavailable,
+		Message: fmt.Sprintf("Model %s is temporarily unavailable", modelName),
+		Type:    "circuit_open",
+		Details: details,
+	}
+}
+
+// upstreamResult classifies the outcome of a request attempt for the circuit
+// breaker: transport errors and 5xx responses are failures unless the caller
+// gave up
+func upstreamResult(ctxErr error, status int) breakerResult {
+	switch {
+	case ctxErr != nil:
+		return breakerAbandoned
+	case status == 0 || status >= http.StatusInternalServerError:
+		return breakerFailure
+	}
+	return breakerSuccess
+}
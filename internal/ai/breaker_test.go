@@ -0,0 +1,230 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newCircuitBreakers(config.CircuitBreakerConfig{
+		ErrorRatio:  0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		OpenTimeout: 30 * time.Second,
+	}, metrics.New())
+	b.now = func() time.Time { return now }
+
+	wantState := func(want breakerState) {
+		t.Helper()
+		statuses := b.statuses()
+		if len(statuses) != 1 || statuses[0].State != want.String() {
+			t.Fatalf("statuses() = %+v, want state %v", statuses, want)
+		}
+	}
+	wantRejected := func() {
+		t.Helper()
+		err := b.allow("gpt2")
+		if errResp, ok := err.(*model.ErrorResponse); !ok || errResp.Type != "circuit_open" || errResp.Code != http.StatusServiceUnavailable {
+			t.Fatalf("allow() error = %v, want circuit_open", err)
+		}
+	}
+
+	// Failures below the minimum number of requests keep the breaker closed
+	for _, result := range []breakerResult{breakerFailure, breakerAbandoned, breakerFailure, breakerSuccess} {
+		if err := b.allow("gpt2"); err != nil {
+			t.Fatalf("allow() unexpected error = %v", err)
+		}
+		b.record("gpt2", result)
+	}
+	wantState(breakerClosed)
+
+	b.record("gpt2", breakerSuccess)
+	wantState(breakerOpen)
+	wantRejected()
+
+	// After the open timeout a single probe is let through
+	now = now.Add(31 * time.Second)
+	if err := b.allow("gpt2"); err != nil {
+		t.Fatalf("allow() probe unexpected error = %v", err)
+	}
+	wantState(breakerHalfOpen)
+	wantRejected()
+
+	b.record("gpt2", breakerFailure)
+	wantState(breakerOpen)
+	wantRejected()
+
+	now = now.Add(31 * time.Second)
+	if err := b.allow("gpt2"); err != nil {
+		t.Fatalf("allow() probe unexpected error = %v", err)
+	}
+	b.record("gpt2", breakerSuccess)
+	wantState(breakerClosed)
+	if err := b.allow("gpt2"); err != nil {
+		t.Errorf("allow() after closing unexpected error = %v", err)
+	}
+
+	disabled := newCircuitBreakers(config.CircuitBreakerConfig{}, nil)
+	for i := 0; i < 10; i++ {
+		disabled.record("gpt2", breakerFailure)
+	}
+	if err := disabled.allow("gpt2"); err != nil {
+		t.Errorf("disabled allow() unexpected error = %v", err)
+	}
+}
+
+func TestMakeRequestCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, `{"error": "internal error"}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{
+		BaseURL: server.URL,
+		Retry:   config.RetryConfig{Attempts: 3},
+		CircuitBreaker: config.CircuitBreakerConfig{
+			ErrorRatio:  0.5,
+			MinRequests: 2,
+			Window:      time.Minute,
+			OpenTimeout: time.Minute,
+		},
+	}, nil, metrics.New(), logger.NewNoopLogger())
+
+	// The breaker opens after the second failed attempt and stops the retries
+	if _, err := s.makeRequest(context.Background(), "gpt2", &HuggingFaceRequest{Inputs: "hi"}); err == nil {
+		t.Fatal("makeRequest() expected error but got nil")
+	}
+	if requests != 2 {
+		t.Errorf("makeRequest() sent %v requests, want 2", requests)
+	}
+
+	_, err := s.makeRequest(context.Background(), "gpt2", &HuggingFaceRequest{Inputs: "hi"})
+	if errResp, ok := err.(*model.ErrorResponse); !ok || errResp.Type != "circuit_open" {
+		t.Errorf("makeRequest() error = %v, want circuit_open", err)
+	}
+	if requests != 2 {
+		t.Errorf("makeRequest() with an open breaker sent %v requests, want 2", requests)
+	}
+	if statuses := s.CircuitBreakers(); len(statuses) != 1 || statuses[0].State != "open" {
+		t.Errorf("CircuitBreakers() = %+v, want gpt2 open", statuses)
+	}
+}
+
+func TestCircuitBreakersLimitModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "internal error"}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		HuggingFace: config.HuggingFaceConfig{
+			BaseURL: server.URL,
+			CircuitBreaker: config.CircuitBreakerConfig{
+				ErrorRatio:  0.5,
+				MinRequests: 1,
+				Window:      time.Minute,
+				OpenTimeout: time.Minute,
+			},
+		},
+		Models:    config.ModelsConfig{Models: []config.ModelConfig{{ID: "gpt2", Task: "text-generation"}}},
+		Fallbacks: config.FallbackConfig{Models: map[string]string{"gpt2": "gpt2-medium"}},
+	}
+	s := NewHuggingFaceService(&cfg.HuggingFace, nil, metrics.New(), logger.NewNoopLogger())
+	s.LimitModels(cfg.KnownModels())
+
+	for _, name := range []string{"gpt2", "gpt2-medium", "made-up-1", "made-up-2"} {
+		if _, err := s.makeRequest(context.Background(), name, &HuggingFaceRequest{Inputs: "hi"}); err == nil {
+			t.Fatalf("makeRequest(%q) expected error but got nil", name)
+		}
+	}
+	// The fallback model has a breaker of its own
+	statuses := s.CircuitBreakers()
+	if len(statuses) != 2 || statuses[0].Model != "gpt2" || statuses[1].Model != "gpt2-medium" {
+		t.Errorf("CircuitBreakers() = %+v, want gpt2 and gpt2-medium", statuses)
+	}
+
+	// Unconfigured models are never rejected by a breaker
+	_, err := s.makeRequest(context.Background(), "made-up-1", &HuggingFaceRequest{Inputs: "hi"})
+	if errResp, ok := err.(*model.ErrorResponse); ok && errResp.Type == "circuit_open" {
+		t.Errorf("makeRequest() error = %v, want the upstream error", err)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			http.Error(w, `{"error": "internal error"}`, http.StatusInternalServerError)
+		case 2:
+			// The probe outlives its caller
+			<-release
+		default:
+			w.Write([]byte(`[{"generated_text": "ok"}]`))
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{
+		BaseURL: server.URL,
+		CircuitBreaker: config.CircuitBreakerConfig{
+			ErrorRatio:  0.5,
+			MinRequests: 1,
+			Window:      time.Minute,
+			OpenTimeout: time.Minute,
+		},
+	}, nil, metrics.New(), logger.NewNoopLogger())
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.breakers.now = func() time.Time { return now }
+
+	if _, err := s.makeRequest(context.Background(), "gpt2", &HuggingFaceRequest{Inputs: "hi"}); err == nil {
+		t.Fatal("makeRequest() expected error but got nil")
+	}
+
+	// The probe is cancelled before the model answers
+	now = now.Add(2 * time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.makeRequest(ctx, "gpt2", &HuggingFaceRequest{Inputs: "hi"}); err == nil {
+		t.Fatal("makeRequest() of the cancelled probe expected error but got nil")
+	}
+
+	// The next request is let through as a new probe and closes the breaker
+	if _, err := s.makeRequest(context.Background(), "gpt2", &HuggingFaceRequest{Inputs: "hi"}); err != nil {
+		t.Fatalf("makeRequest() after a cancelled probe unexpected error = %v", err)
+	}
+	if statuses := s.CircuitBreakers(); len(statuses) != 1 || statuses[0].State != "closed" {
+		t.Errorf("CircuitBreakers() = %+v, want gpt2 closed", statuses)
+	}
+
+	// A probe that never reports its outcome is replaced after the open timeout
+	b := s.breakers
+	b.mu.Lock()
+	b.transition("gpt2", b.breaker("gpt2"), breakerOpen)
+	b.mu.Unlock()
+	now = now.Add(2 * time.Minute)
+	if err := b.allow("gpt2"); err != nil {
+		t.Fatalf("allow() probe unexpected error = %v", err)
+	}
+	if err := b.allow("gpt2"); err == nil {
+		t.Fatal("allow() during the probe expected circuit_open but got nil")
+	}
+	now = now.Add(2 * time.Minute)
+	if err := b.allow("gpt2"); err != nil {
+		t.Errorf("allow() after a lost probe unexpected error = %v", err)
+	}
+}
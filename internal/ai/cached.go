@@ -0,0 +1,200 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/cache"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// CachedService decorates a model.AIService with a response cache for
+// deterministic requests: generations with temperature 0, embeddings and the
+// task pipelines (sentiment, summarization, classification, entities,
+// translation, question answering). Streaming requests always reach the wrapped service.
+// Keys hold the requested model, so the cache must sit below FallbackService:
+// above it, a fallback model's response would be served for the primary.
+type CachedService struct {
+	model.AIService
+	store   cache.Store
+	ttl     time.Duration
+	metrics *metrics.Metrics
+	logger  logger.Logger
+}
+
+// NewCachedService wraps service with a cache backed by store
+func NewCachedService(service model.AIService, store cache.Store, ttl time.Duration, metrics *metrics.Metrics, logger logger.Logger) *CachedService {
+	return &CachedService{
+		AIService: service,
+		store:     store,
+		ttl:       ttl,
+		metrics:   metrics,
+		logger:    logger,
+	}
+}
+
+// GenerateText implements model.AIService
+func (s *CachedService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	if !isDeterministic(req) {
+		return s.AIService.GenerateText(ctx, req)
+	}
+	return s.cachedGeneration(ctx, "generate", req, s.AIService.GenerateText)
+}
+
+// GenerateCompletion implements model.AIService
+func (s *CachedService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	if !isDeterministic(req) {
+		return s.AIService.GenerateCompletion(ctx, req)
+	}
+	return s.cachedGeneration(ctx, "complete", req, s.AIService.GenerateCompletion)
+}
+
+// AnalyzeSentiment implements model.AIService
+func (s *CachedService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+	key := cacheKey("sentiment", req.Model, req.Text)
+	return cached(ctx, s, key, func() (*model.SentimentResponse, error) {
+		return s.AIService.AnalyzeSentiment(ctx, req)
+	})
+}
+
+// SummarizeText implements model.AIService
+func (s *CachedService) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+	key := cacheKey("summarize", req.Model, req.Text, req.MaxLength, req.Strategy)
+	return cached(ctx, s, key, func() (*model.SummaryResponse, error) {
+		return s.AIService.SummarizeText(ctx, req)
+	})
+}
+
+// Embed implements model.AIService
+func (s *CachedService) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	key := cacheKey("embed", req.Model, req.Input, req.Normalize, req.Pooling)
+	return cached(ctx, s, key, func() (*model.EmbeddingResponse, error) {
+		return s.AIService.Embed(ctx, req)
+	})
+}
+
+// ClassifyZeroShot implements model.AIService
+func (s *CachedService) ClassifyZeroShot(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+	key := cacheKey("classify", req.Model, req.Text, req.Labels, req.HypothesisTemplate, req.MultiLabel)
+	return cached(ctx, s, key, func() (*model.ClassificationResponse, error) {
+		return s.AIService.ClassifyZeroShot(ctx, req)
+	})
+}
+
+// ExtractEntities implements model.AIService
+func (s *CachedService) ExtractEntities(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+	key := cacheKey("entities", req.Model, req.Text, req.AggregationStrategy, req.MinScore)
+	return cached(ctx, s, key, func() (*model.EntityResponse, error) {
+		return s.AIService.ExtractEntities(ctx, req)
+	})
+}
+
+// Translate implements model.AIService
+func (s *CachedService) Translate(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+	key := cacheKey("translate", req.Model, req.Text, req.SourceLang, req.TargetLang)
+	return cached(ctx, s, key, func() (*model.TranslationResponse, error) {
+		return s.AIService.Translate(ctx, req)
+	})
+}
+
+// AnswerQuestion implements model.AIService
+func (s *CachedService) AnswerQuestion(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+	key := cacheKey("qa", question, contextText)
+	return cached(ctx, s, key, func() (*model.QAResponse, error) {
+		return s.AIService.AnswerQuestion(ctx, question, contextText)
+	})
+}
+
+// cachedGeneration serves a generation from the cache, rewriting the
+// response ID so that callers always see their own request ID
+func (s *CachedService) cachedGeneration(ctx context.Context, op string, req *model.AIRequest, generate func(context.Context, *model.AIRequest) (*model.AIResponse, error)) (*model.AIResponse, error) {
+	start := time.Now()
+	key := cacheKey(op, req.Model, req.Prompt, req.MaxTokens, req.TopP, req.Parameters)
+	resp, err := cached(ctx, s, key, func() (*model.AIResponse, error) {
+		return generate(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.ID != req.ID {
+		resp.ID = req.ID
+		resp.ProcessingMs = time.Since(start).Milliseconds()
+	}
+	return resp, nil
+}
+
+// cached returns the value stored under key or calls fetch and stores its
+// result. Values are stored JSON-encoded so every hit returns a fresh copy.
+func cached[T any](ctx context.Context, s *CachedService, key string, fetch func() (*T, error)) (*T, error) {
+	lookup := cache.LookupFromContext(ctx)
+	if lookup != nil && lookup.Bypass {
+		lookup.Status = cache.StatusBypass
+		s.metrics.ObserveCache(cache.StatusBypass)
+		return fetch()
+	}
+
+	if data, ok := s.store.Get(key); ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			if lookup != nil {
+				lookup.Status = cache.StatusHit
+			}
+			s.metrics.ObserveCache(cache.StatusHit)
+			return &value, nil
+		}
+		s.store.Delete(key)
+	}
+
+	if lookup != nil {
+		lookup.Status = cache.StatusMiss
+	}
+	s.metrics.ObserveCache(cache.StatusMiss)
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to encode response for cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return value, nil
+	}
+	s.store.Set(key, data, s.ttl)
+	return value, nil
+}
+
+// isDeterministic reports whether a generation request always produces the
+// same output and can therefore be cached
+func isDeterministic(req *model.AIRequest) bool {
+	return req.Temperature == 0 && !samples(req.Parameters)
+}
+
+// samples reports whether upstream parameters ask for sampled output, through
+// do_sample or a non-zero temperature
+func samples(parameters map[string]interface{}) bool {
+	if doSample, ok := parameters["do_sample"].(bool); ok && doSample {
+		return true
+	}
+	temperature, ok := number(parameters["temperature"])
+	return ok && temperature != 0
+}
+
+// cacheKey hashes the operation and its normalized inputs. encoding/json
+// sorts map keys, so parameter maps hash identically regardless of order.
+func cacheKey(op string, parts ...interface{}) string {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		// Unencodable parameters; fall back to a key that never matches
+		data = []byte(time.Now().String())
+	}
+	sum := sha256.Sum256(append([]byte(op+"\x00"), data...))
+	return op + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,330 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// maxCatalogEntries bounds the catalog, which holds the models clients have
+// looked up. The oldest entries of unknown models are evicted beyond it.
+const maxCatalogEntries = 1000
+
+// hubExpand lists the model-info fields requested from the Hub
+var hubExpand = []string{"pipeline_tag", "library_name", "cardData", "tags", "gated", "private", "inference"}
+
+// hubModelInfo is the part of the Hub model-info response used here
+type hubModelInfo struct {
+	ID          string      `json:"id"`
+	PipelineTag string      `json:"pipeline_tag"`
+	LibraryName string      `json:"library_name"`
+	Tags        []string    `json:"tags"`
+	Gated       interface{} `json:"gated"` // false, "auto" or "manual"
+	Private     bool        `json:"private"`
+	Inference   string      `json:"inference"` // "warm" when deployed on the Inference API
+	CardData    struct {
+		License interface{} `json:"license"` // a string or a list of strings
+	} `json:"cardData"`
+}
+
+// toModelInfo converts a Hub response into a ModelInfo
+func (h *hubModelInfo) toModelInfo(fetchedAt time.Time) *model.ModelInfo {
+	gated := false
+	switch v := h.Gated.(type) {
+	case bool:
+		gated = v
+	case string:
+		gated = v != ""
+	}
+
+	return &model.ModelInfo{
+		ID:          h.ID,
+		Provider:    config.ProviderHuggingFace,
+		PipelineTag: h.PipelineTag,
+		License:     h.license(),
+		LibraryName: h.LibraryName,
+		Gated:       gated,
+		Private:     h.Private,
+		Deployable:  h.Inference == "warm",
+		Source:      model.ModelSourceHub,
+		FetchedAt:   &fetchedAt,
+	}
+}
+
+// license prefers the model card's license over the "license:" tag
+func (h *hubModelInfo) license() string {
+	switch v := h.CardData.License.(type) {
+	case string:
+		return v
+	case []interface{}:
+		if len(v) > 0 {
+			if s, ok := v[0].(string); ok {
+				return s
+			}
+		}
+	}
+	for _, tag := range h.Tags {
+		if license, ok := strings.CutPrefix(tag, "license:"); ok {
+			return license
+		}
+	}
+	return ""
+}
+
+// catalog caches model metadata in memory and, when a path is configured, in
+// a JSON file of the form
+//
+//	{"models": [{"id": "gpt2", "pipeline_tag": "text-generation", ...}]}
+//
+// The file is rewritten after every Hub lookup so that it can serve as a
+// fallback when the Hub is unreachable. It may also be written by hand for
+// offline deployments. Only models the Hub resolved are added, and beyond
+// maxEntries the least recently fetched entries of unknown models are evicted.
+type catalog struct {
+	path       string
+	ttl        time.Duration // entries older than this are refreshed from the Hub; 0 keeps them forever
+	now        func() time.Time
+	maxEntries int
+	known      func(modelName string) bool // models never evicted; nil for none
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]model.ModelInfo
+}
+
+func newCatalog(path string, ttl time.Duration) *catalog {
+	return &catalog{
+		path:       path,
+		ttl:        ttl,
+		now:        time.Now,
+		maxEntries: maxCatalogEntries,
+		entries:    make(map[string]model.ModelInfo),
+	}
+}
+
+// get returns the entry for a model and whether it is still fresh
+func (c *catalog) get(modelName string) (info model.ModelInfo, fresh bool, ok bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return model.ModelInfo{}, false, false, err
+	}
+	info, ok = c.entries[modelName]
+	if !ok {
+		return model.ModelInfo{}, false, false, nil
+	}
+	fresh = c.ttl <= 0 || (info.FetchedAt != nil && c.now().Sub(*info.FetchedAt) < c.ttl)
+	return info, fresh, true, nil
+}
+
+// put stores the entry of a model and persists the catalog file. The Hub
+// may report a canonical ID differing from the requested name (e.g.
+// "openai-community/gpt2" for "gpt2"), so entries are keyed by the name.
+func (c *catalog) put(modelName string, info model.ModelInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A broken file must not prevent caching in memory; it is overwritten
+	// below
+	loadErr := c.load()
+	c.entries[modelName] = info
+	c.evict(modelName)
+	if c.path == "" {
+		return loadErr
+	}
+	if err := c.save(); err != nil {
+		return err
+	}
+	return loadErr
+}
+
+// evict removes the least recently fetched entries, other than keep and
+// those of known models, until the catalog is within maxEntries. Callers must
+// hold c.mu.
+func (c *catalog) evict(keep string) {
+	for len(c.entries) > c.maxEntries {
+		oldest := ""
+		var oldestAt time.Time
+		for name, info := range c.entries {
+			if name == keep || (c.known != nil && c.known(name)) {
+				continue
+			}
+			var fetchedAt time.Time
+			if info.FetchedAt != nil {
+				fetchedAt = *info.FetchedAt
+			}
+			if oldest == "" || fetchedAt.Before(oldestAt) {
+				oldest, oldestAt = name, fetchedAt
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// load reads the catalog file once. Callers must hold c.mu.
+func (c *catalog) load() error {
+	if c.loaded || c.path == "" {
+		return nil
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read model catalog: %w", err)
+	}
+
+	var file struct {
+		Models []model.ModelInfo `json:"models"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse model catalog: %w", err)
+	}
+	for _, info := range file.Models {
+		if info.ID == "" {
+			continue
+		}
+		if info.Provider == "" {
+			info.Provider = config.ProviderHuggingFace
+		}
+		if _, ok := c.entries[info.ID]; !ok {
+			c.entries[info.ID] = info
+		}
+	}
+	return nil
+}
+
+// save atomically rewrites the catalog file. Callers must hold c.mu.
+func (c *catalog) save() error {
+	models := make([]model.ModelInfo, 0, len(c.entries))
+	for name, info := range c.entries {
+		info.ID = name
+		models = append(models, info)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].ID < models[j].ID
+	})
+
+	data, err := json.MarshalIndent(map[string]interface{}{"models": models}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode model catalog: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".catalog-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write model catalog: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write model catalog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write model catalog: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write model catalog: %w", err)
+	}
+	return nil
+}
+
+// modelInfo returns the metadata of a model. Fresh catalog entries are served
+// directly; otherwise the Hub is queried, falling back to the catalog when it
+// cannot be reached. Models the Hub does not know are reported as a 404
+// *model.ErrorResponse.
+func (s *HuggingFaceService) modelInfo(ctx context.Context, modelName string) (*model.ModelInfo, error) {
+	cachedInfo, fresh, cachedOK, err := s.catalog.get(modelName)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to load model catalog", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if cachedOK && fresh {
+		return &cachedInfo, nil
+	}
+
+	info, err := s.fetchModelInfo(ctx, modelName)
+	if err == nil {
+		if err := s.catalog.put(modelName, *info); err != nil {
+			s.logger.Warn(ctx, "Failed to update model catalog", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		return info, nil
+	}
+	if _, notFound := err.(*model.ErrorResponse); notFound || !cachedOK {
+		return nil, err
+	}
+
+	s.logger.Warn(ctx, "Hub unavailable, using cataloged model info", map[string]interface{}{
+		"model": modelName,
+		"error": err.Error(),
+	})
+	cachedInfo.Source = model.ModelSourceCatalog
+	return &cachedInfo, nil
+}
+
+// fetchModelInfo queries the Hub model-info API
+func (s *HuggingFaceService) fetchModelInfo(ctx context.Context, modelName string) (*model.ModelInfo, error) {
+	query := url.Values{"expand[]": hubExpand}
+	endpoint := fmt.Sprintf("%s/api/models/%s?%s", strings.TrimRight(s.config.HubURL, "/"), escapeModelPath(modelName), query.Encode())
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+s.config.APIKey)
+	httpReq.Header.Set("User-Agent", "go-ai-huggingface/1.0")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("hub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, &model.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Model %q was not found on the Hugging Face Hub", modelName),
+			Type:    "model_not_found",
+		}
+	default:
+		return nil, fmt.Errorf("hub returned status %d", resp.StatusCode)
+	}
+
+	var hubInfo hubModelInfo
+	if err := json.NewDecoder(resp.Body).Decode(&hubInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse model info: %w", err)
+	}
+	if hubInfo.ID == "" {
+		hubInfo.ID = modelName
+	}
+	return hubInfo.toModelInfo(time.Now()), nil
+}
+
+// escapeModelPath escapes each segment of an "org/name" model ID
+func escapeModelPath(modelName string) string {
+	segments := strings.Split(modelName, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
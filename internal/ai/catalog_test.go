@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestHubModelInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		hub  hubModelInfo
+		want model.ModelInfo
+	}{
+		{
+			name: "card license and manual gating",
+			hub: hubModelInfo{
+				ID:          "meta-llama/Llama-3.1-8B-Instruct",
+				PipelineTag: "text-generation",
+				Gated:       "manual",
+				Inference:   "warm",
+				Tags:        []string{"license:other"},
+			},
+			want: model.ModelInfo{
+				ID:          "meta-llama/Llama-3.1-8B-Instruct",
+				PipelineTag: "text-generation",
+				License:     "llama3.1",
+				Gated:       true,
+				Deployable:  true,
+			},
+		},
+		{
+			name: "tag license and not deployed",
+			hub: hubModelInfo{
+				ID:        "org/model",
+				Gated:     false,
+				Inference: "cold",
+				Tags:      []string{"pytorch", "license:apache-2.0"},
+			},
+			want: model.ModelInfo{
+				ID:      "org/model",
+				License: "apache-2.0",
+			},
+		},
+	}
+	tests[0].hub.CardData.License = "llama3.1"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			got := tt.hub.toModelInfo(fetchedAt)
+			if got.FetchedAt == nil || !got.FetchedAt.Equal(fetchedAt) {
+				t.Errorf("toModelInfo() FetchedAt = %v, want %v", got.FetchedAt, fetchedAt)
+			}
+			got.FetchedAt = nil
+			tt.want.Provider = config.ProviderHuggingFace
+			tt.want.Source = model.ModelSourceHub
+			if *got != tt.want {
+				t.Errorf("toModelInfo() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateModelCatalog(t *testing.T) {
+	lookups := 0
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.URL.Path != "/api/models/gpt2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": "openai-community/gpt2", "pipeline_tag": "text-generation", "gated": false,
+			"inference": "warm", "cardData": {"license": "mit"}}`))
+	}))
+	defer hub.Close()
+
+	cfg := &config.HuggingFaceConfig{
+		HubURL:           hub.URL,
+		ModelCatalogFile: filepath.Join(t.TempDir(), "catalog.json"),
+		ModelInfoTTL:     time.Hour,
+	}
+	ctx := context.Background()
+
+	s := NewHuggingFaceService(cfg, nil, metrics.New(), logger.NewNoopLogger())
+	info, err := s.ValidateModel(ctx, "gpt2")
+	if err != nil {
+		t.Fatalf("ValidateModel() unexpected error = %v", err)
+	}
+	if info.PipelineTag != "text-generation" || info.License != "mit" || !info.Deployable || info.Source != model.ModelSourceHub {
+		t.Errorf("ValidateModel() = %+v, want a deployable MIT text-generation model from the hub", info)
+	}
+
+	if _, err := s.ValidateModel(ctx, "gpt2"); err != nil || lookups != 1 {
+		t.Errorf("ValidateModel() of a fresh entry made %d lookups, error = %v; want 1 lookup", lookups, err)
+	}
+
+	_, err = s.ValidateModel(ctx, "org/missing")
+	if errResp, ok := err.(*model.ErrorResponse); !ok || errResp.Code != http.StatusNotFound {
+		t.Errorf("ValidateModel() of an unknown model error = %v, want a 404 error response", err)
+	}
+
+	if _, err := os.Stat(cfg.ModelCatalogFile); err != nil {
+		t.Fatalf("catalog file was not written: %v", err)
+	}
+
+	// A new instance with the Hub down serves the persisted catalog, even
+	// though its entries are stale
+	hub.Close()
+	cfg.ModelInfoTTL = time.Nanosecond
+	offline := NewHuggingFaceService(cfg, nil, metrics.New(), logger.NewNoopLogger())
+	info, err = offline.ValidateModel(ctx, "gpt2")
+	if err != nil {
+		t.Fatalf("ValidateModel() offline unexpected error = %v", err)
+	}
+	if info.Source != model.ModelSourceCatalog || info.License != "mit" {
+		t.Errorf("ValidateModel() offline = %+v, want the cataloged entry", info)
+	}
+
+	if _, err := offline.ValidateModel(ctx, "distilgpt2"); err == nil {
+		t.Errorf("ValidateModel() offline for an uncataloged model expected error but got nil")
+	}
+}
+
+func TestCatalogEviction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	c := newCatalog(path, 0)
+	c.maxEntries = 2
+	c.known = func(modelName string) bool { return modelName == "gpt2" }
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"gpt2", "org/a", "org/b", "org/c"} {
+		fetchedAt := start.Add(time.Duration(i) * time.Minute)
+		if err := c.put(name, model.ModelInfo{ID: name, FetchedAt: &fetchedAt}); err != nil {
+			t.Fatalf("put(%q) unexpected error = %v", name, err)
+		}
+	}
+
+	// The registered model is kept although it is the oldest
+	reloaded := newCatalog(path, 0)
+	for name, want := range map[string]bool{"gpt2": true, "org/a": false, "org/b": false, "org/c": true} {
+		if _, _, ok, err := reloaded.get(name); err != nil || ok != want {
+			t.Errorf("get(%q) cataloged = %v, error = %v; want %v", name, ok, err, want)
+		}
+	}
+	if len(reloaded.entries) != 2 {
+		t.Errorf("catalog file holds %d entries, want 2", len(reloaded.entries))
+	}
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// splitText splits text into chunks of at most maxChars characters,
+// preferring to cut after a paragraph break in the second half of a chunk,
+// then after a sentence end, then at whitespace, and only mid-word as a last
+// resort. Concatenating the chunks yields text.
+func splitText(text string, maxChars int) []string {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return []string{text}
+	}
+
+	var chunks []string
+	start := 0
+	for len(runes)-start > maxChars {
+		end := start + maxChars
+		cut := lastParagraph(runes[start:end])
+		if cut < maxChars/2 {
+			cut = lastBoundary(runes[start:end], isSentenceEnd)
+		}
+		if cut == 0 {
+			cut = lastBoundary(runes[start:end], unicode.IsSpace)
+		}
+		if cut == 0 {
+			cut = maxChars
+		}
+		chunks = append(chunks, string(runes[start:start+cut]))
+		start += cut
+	}
+	return append(chunks, string(runes[start:]))
+}
+
+// textWindow is a slice of a longer text and its offset in characters
+type textWindow struct {
+	Offset int
+	Text   string
+}
+
+// overlappingWindows splits text into windows of at most size characters,
+// each sharing about overlap characters with the previous one so that a span
+// cut by one window boundary is whole in the next. Windows end at whitespace
+// and start at a word where possible.
+func overlappingWindows(text string, size, overlap int) []textWindow {
+	runes := []rune(text)
+	if size <= 0 || len(runes) <= size {
+		return []textWindow{{Offset: 0, Text: text}}
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var windows []textWindow
+	start := 0
+	for {
+		end := start + size
+		if end >= len(runes) {
+			return append(windows, textWindow{Offset: start, Text: string(runes[start:])})
+		}
+		if cut := lastBoundary(runes[start:end], unicode.IsSpace); cut > overlap {
+			end = start + cut
+		}
+		windows = append(windows, textWindow{Offset: start, Text: string(runes[start:end])})
+
+		next := end - overlap
+		for i := next; i < end-1; i++ {
+			if unicode.IsSpace(runes[i]) {
+				next = i + 1
+				break
+			}
+		}
+		start = next
+	}
+}
+
+// lastBoundary returns the position just after the last rune in window
+// matching boundary, or 0 when there is none
+func lastBoundary(window []rune, boundary func(rune) bool) int {
+	for i := len(window) - 1; i > 0; i-- {
+		if boundary(window[i]) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// lastParagraph returns the position just after the last blank line in
+// window, or 0 when there is none
+func lastParagraph(window []rune) int {
+	for i := len(window) - 1; i > 0; i-- {
+		if window[i] == '\n' && window[i-1] == '\n' {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func isSentenceEnd(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '\n' || r == '。'
+}
+
+// splitSpace separates leading and trailing whitespace from s so that it can
+// be restored around a transformed version of s
+func splitSpace(s string) (leading, core, trailing string) {
+	core = strings.TrimLeftFunc(s, unicode.IsSpace)
+	leading = s[:len(s)-len(core)]
+	trimmed := strings.TrimRightFunc(core, unicode.IsSpace)
+	trailing = core[len(trimmed):]
+	return leading, trimmed, trailing
+}
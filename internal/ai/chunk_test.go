@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{
+			name:     "short text",
+			text:     "One sentence.",
+			maxChars: 100,
+			want:     []string{"One sentence."},
+		},
+		{
+			name:     "sentence boundaries",
+			text:     "First one. Second one. Third one.",
+			maxChars: 24,
+			want:     []string{"First one. Second one.", " Third one."},
+		},
+		{
+			name:     "whitespace fallback",
+			text:     "no sentence ends here at all",
+			maxChars: 12,
+			want:     []string{"no sentence ", "ends here ", "at all"},
+		},
+		{
+			name:     "paragraph boundaries",
+			text:     "First paragraph. Still first.\n\nNext. Second one more.",
+			maxChars: 40,
+			want:     []string{"First paragraph. Still first.\n\n", "Next. Second one more."},
+		},
+		{
+			name:     "paragraph too early",
+			text:     "Hi.\n\nA longer paragraph. With sentences.",
+			maxChars: 30,
+			want:     []string{"Hi.\n\nA longer paragraph.", " With sentences."},
+		},
+		{
+			name:     "hard cut",
+			text:     "abcdefghij",
+			maxChars: 4,
+			want:     []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "multibyte characters",
+			text:     "Grüße. Schön.",
+			maxChars: 7,
+			want:     []string{"Grüße.", " Schön."},
+		},
+		{
+			name:     "unlimited",
+			text:     "abc",
+			maxChars: 0,
+			want:     []string{"abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitText(tt.text, tt.maxChars)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitText() = %q, want %q", got, tt.want)
+			}
+			if joined := strings.Join(got, ""); joined != tt.text {
+				t.Errorf("splitText() chunks join to %q, want %q", joined, tt.text)
+			}
+		})
+	}
+}
+
+func TestSplitSpace(t *testing.T) {
+	leading, core, trailing := splitSpace("\n  Hello world. \n")
+	if leading != "\n  " || core != "Hello world." || trailing != " \n" {
+		t.Errorf("splitSpace() = %q, %q, %q", leading, core, trailing)
+	}
+
+	leading, core, trailing = splitSpace("   ")
+	if leading != "   " || core != "" || trailing != "" {
+		t.Errorf("splitSpace() of blank = %q, %q, %q", leading, core, trailing)
+	}
+}
+
+func TestOverlappingWindows(t *testing.T) {
+	text := "alpha beta gamma delta epsilon zeta eta theta"
+
+	windows := overlappingWindows(text, 20, 8)
+	want := []textWindow{
+		{Offset: 0, Text: "alpha beta gamma "},
+		{Offset: 11, Text: "gamma delta epsilon "},
+		{Offset: 23, Text: "epsilon zeta eta "},
+		{Offset: 36, Text: "eta theta"},
+	}
+	if !reflect.DeepEqual(windows, want) {
+		t.Fatalf("overlappingWindows() = %+v, want %+v", windows, want)
+	}
+
+	runes := []rune(text)
+	for i, window := range windows {
+		if got := string(runes[window.Offset : window.Offset+len([]rune(window.Text))]); got != window.Text {
+			t.Errorf("window %d text = %q, want %q at offset %d", i, window.Text, got, window.Offset)
+		}
+	}
+
+	if got := overlappingWindows(text, 0, 8); len(got) != 1 || got[0].Text != text {
+		t.Errorf("overlappingWindows() without size = %+v, want the whole text", got)
+	}
+	if got := overlappingWindows("abcdefghij", 4, 4); len(got) != 3 {
+		t.Errorf("overlappingWindows() with overlap >= size = %+v, want 3 windows", got)
+	}
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces identical in-flight upstream requests: the first
+// caller starts the request and callers arriving before it completes wait
+// for the same result. Each caller stops waiting when its own context ends;
+// the request itself is cancelled once no caller is waiting any more.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is an upstream request shared by its waiting callers
+type flight struct {
+	done     chan struct{}
+	body     []byte
+	err      error
+	waiters  int
+	deadline time.Time // zero when the request has none
+	cancel   context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// do returns the result of fn for key, calling it only if no call for key is
+// in flight. fn runs with the values and the deadline of the caller starting
+// it but is only cancelled when every caller has given up. A caller whose
+// deadline is later than that of the call in flight starts a new call, which
+// later callers join, so a call never ends before the deadline of a waiter.
+// shared reports whether the caller joined a call started by another caller.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) (body []byte, shared bool, err error) {
+	deadline, _ := ctx.Deadline()
+
+	g.mu.Lock()
+	f, shared := g.flights[key]
+	if shared && !f.deadline.IsZero() && (deadline.IsZero() || deadline.After(f.deadline)) {
+		shared = false
+	}
+	if !shared {
+		flightCtx, cancel := context.WithoutCancel(ctx), context.CancelFunc(nil)
+		if deadline.IsZero() {
+			flightCtx, cancel = context.WithCancel(flightCtx)
+		} else {
+			flightCtx, cancel = context.WithDeadline(flightCtx, deadline)
+		}
+		f = &flight{done: make(chan struct{}), deadline: deadline, cancel: cancel}
+		g.flights[key] = f
+		go g.run(flightCtx, key, f, fn)
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.body, shared, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody wants the result any more; later callers start afresh
+			f.cancel()
+			g.forget(key, f)
+		}
+		g.mu.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+// run performs the shared call and hands its result to the waiting callers
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) ([]byte, error)) {
+	f.body, f.err = fn(ctx)
+
+	g.mu.Lock()
+	g.forget(key, f)
+	g.mu.Unlock()
+	f.cancel()
+	close(f.done)
+}
+
+// forget removes f from the group unless a newer flight replaced it. g.mu
+// must be held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
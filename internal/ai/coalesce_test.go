@@ -0,0 +1,243 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// waitForWaiters blocks until n callers wait for the flight of key
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		f, ok := g.flights[key]
+		waiting := ok && f.waiters == n
+		g.mu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d callers did not join the flight", n)
+}
+
+func TestMakeRequestCoalescing(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`[{"label": "positive", "score": 0.9}]`))
+	}))
+	defer server.Close()
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{
+		BaseURL:          server.URL,
+		CoalesceRequests: true,
+	}, nil, metrics.New(), logger.NewNoopLogger())
+
+	const callers = 5
+	req := &HuggingFaceRequest{Inputs: "I love it"}
+	var wg sync.WaitGroup
+	bodies := make([]string, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := s.makeRequest(context.Background(), "sentiment", req)
+			bodies[i], errs[i] = string(body), err
+		}(i)
+	}
+
+	waitForWaiters(t, s.flights, `sentiment`+"\x00"+`{"inputs":"I love it"}`, callers)
+	close(release)
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("makeRequest() sent %v upstream requests, want 1", got)
+	}
+	for i := range bodies {
+		if errs[i] != nil || bodies[i] != `[{"label": "positive", "score": 0.9}]` {
+			t.Errorf("caller %d got %q, %v", i, bodies[i], errs[i])
+		}
+	}
+
+	// Once the flight has landed, the next request reaches the upstream again
+	if _, err := s.makeRequest(context.Background(), "sentiment", req); err != nil {
+		t.Fatalf("makeRequest() unexpected error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("makeRequest() sent %v upstream requests, want 2", got)
+	}
+}
+
+func TestFlightGroupCancellation(t *testing.T) {
+	g := newFlightGroup()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+	fn := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		select {
+		case <-release:
+			return []byte("done"), nil
+		case <-ctx.Done():
+			close(cancelled)
+			return nil, ctx.Err()
+		}
+	}
+
+	// A caller giving up does not affect the others
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(first, "key", fn)
+		firstErr <- err
+	}()
+	<-started
+
+	secondResult := make(chan string, 1)
+	go func() {
+		body, shared, err := g.do(context.Background(), "key", fn)
+		if err != nil || !shared {
+			t.Errorf("do() shared = %v, error = %v, want a shared result", shared, err)
+		}
+		secondResult <- string(body)
+	}()
+	waitForWaiters(t, g, "key", 2)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled do() error = %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if got := <-secondResult; got != "done" {
+		t.Errorf("do() = %q, want %q", got, "done")
+	}
+
+	// The call is cancelled once every caller has given up
+	started = make(chan struct{})
+	release = make(chan struct{})
+	only, cancelOnly := context.WithCancel(context.Background())
+	go g.do(only, "key", fn)
+	<-started
+	cancelOnly()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("flight was not cancelled after its last caller left")
+	}
+}
+
+func TestFlightGroupDeadlines(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	deadlines := make(chan time.Time, 3)
+	fn := func(ctx context.Context) ([]byte, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines <- deadline
+		<-release
+		return []byte("done"), nil
+	}
+
+	now := time.Now()
+	soon, cancelSoon := context.WithDeadline(context.Background(), now.Add(time.Minute))
+	defer cancelSoon()
+	later, cancelLater := context.WithDeadline(context.Background(), now.Add(time.Hour))
+	defer cancelLater()
+
+	type result struct {
+		shared bool
+		err    error
+	}
+	results := make(chan result, 4)
+	call := func(ctx context.Context) {
+		_, shared, err := g.do(ctx, "key", fn)
+		results <- result{shared, err}
+	}
+
+	// The call runs with the deadline of the caller starting it
+	go call(soon)
+	if got := <-deadlines; !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("flight deadline = %v, want %v", got, now.Add(time.Minute))
+	}
+	// A caller with a later deadline starts a call of its own, which callers
+	// with an earlier deadline join
+	go call(later)
+	if got := <-deadlines; !got.Equal(now.Add(time.Hour)) {
+		t.Errorf("flight deadline = %v, want %v", got, now.Add(time.Hour))
+	}
+	go call(soon)
+	waitForWaiters(t, g, "key", 2)
+	// Without a deadline a caller cannot wait for a call that has one
+	go call(context.Background())
+	if got := <-deadlines; !got.IsZero() {
+		t.Errorf("flight deadline = %v, want none", got)
+	}
+
+	close(release)
+	shared := 0
+	for i := 0; i < 4; i++ {
+		r := <-results
+		if r.err != nil {
+			t.Errorf("do() unexpected error = %v", r.err)
+		}
+		if r.shared {
+			shared++
+		}
+	}
+	if shared != 1 {
+		t.Errorf("do() shared %d calls, want 1", shared)
+	}
+}
+
+func TestMakeRequestSampledNotCoalesced(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`[{"generated_text": "Hello there"}]`))
+	}))
+	defer server.Close()
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{
+		BaseURL:          server.URL,
+		CoalesceRequests: true,
+	}, nil, metrics.New(), logger.NewNoopLogger())
+
+	req := newGenerationRequest(&model.AIRequest{Model: "gpt2", Prompt: "Hello", Temperature: 0.7})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.makeRequest(context.Background(), "gpt2", req); err != nil {
+				t.Errorf("makeRequest() unexpected error = %v", err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for requests.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if got := requests.Load(); got != 2 {
+		t.Errorf("makeRequest() sent %v upstream requests for sampled generations, want 2", got)
+	}
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
+)
+
+// DefaultsService decorates a model.AIService with the defaults of the model
+// registry: generation requests that omit max_tokens or temperature get the
+// parameters registered for their model, or the configured defaults, and
+// prompts that would not fit the model's max context length are rejected.
+// It sits above the cache, whose keys must hold the applied defaults, and
+// below FallbackService so that every model of a chain gets its own.
+type DefaultsService struct {
+	model.AIService
+	models   *config.ModelsConfig
+	defaults *config.HuggingFaceConfig
+	tokens   *tokenizer.Counter
+}
+
+// NewDefaultsService wraps service with the defaults of the models registry
+func NewDefaultsService(service model.AIService, models *config.ModelsConfig, defaults *config.HuggingFaceConfig, tokens *tokenizer.Counter) *DefaultsService {
+	return &DefaultsService{
+		AIService: service,
+		models:    models,
+		defaults:  defaults,
+		tokens:    tokens,
+	}
+}
+
+// GenerateText implements model.AIService
+func (s *DefaultsService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	req, err := s.apply(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.AIService.GenerateText(ctx, req)
+}
+
+// GenerateCompletion implements model.AIService
+func (s *DefaultsService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	req, err := s.apply(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.AIService.GenerateCompletion(ctx, req)
+}
+
+// GenerateTextStream implements model.AIService
+func (s *DefaultsService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	req, err := s.apply(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.AIService.GenerateTextStream(ctx, req, fn)
+}
+
+// apply returns a copy of req with the defaults of its model, or a
+// validation error when the prompt and max_tokens exceed its context length
+func (s *DefaultsService) apply(req *model.AIRequest) (*model.AIRequest, error) {
+	entry, registered := s.models.Lookup(req.Model)
+	if !registered {
+		entry = &config.ModelConfig{}
+	}
+
+	next := *req
+	if next.MaxTokens == 0 {
+		next.MaxTokens = s.defaults.MaxTokens
+		if maxTokens, ok := number(entry.Parameters["max_tokens"]); ok {
+			next.MaxTokens = int(maxTokens)
+		}
+	}
+	if !next.HasTemperature() {
+		temperature := s.defaults.Temperature
+		if t, ok := number(entry.Parameters["temperature"]); ok {
+			temperature = float32(t)
+		}
+		next.SetTemperature(temperature)
+	}
+
+	if entry.MaxContextLength > 0 && req.Prompt != "" {
+		promptTokens := s.tokens.CountInput(req.Model, req.Prompt)
+		if promptTokens+next.MaxTokens > entry.MaxContextLength {
+			return nil, &model.ErrorResponse{
+				Code: 400,
+				Message: fmt.Sprintf("prompt of %d tokens plus max_tokens of %d exceeds the context length of %s (%d tokens)",
+					promptTokens, next.MaxTokens, req.Model, entry.MaxContextLength),
+				Type: "validation_error",
+			}
+		}
+	}
+	return &next, nil
+}
+
+// number returns a numeric registry parameter, decoded from JSON as float64
+// or set in code as an int or float32
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func TestDefaultsService(t *testing.T) {
+	zero := &model.AIRequest{Model: "gpt2", Prompt: "Hello"}
+	zero.SetTemperature(0)
+
+	tests := []struct {
+		name            string
+		req             *model.AIRequest
+		wantMaxTokens   int
+		wantTemperature float32
+		wantErr         string
+	}{
+		{
+			name:            "registry defaults",
+			req:             &model.AIRequest{Model: "gpt2", Prompt: "Hello"},
+			wantMaxTokens:   64,
+			wantTemperature: 0.5,
+		},
+		{
+			name:            "explicit values",
+			req:             &model.AIRequest{Model: "gpt2", Prompt: "Hello", MaxTokens: 10, Temperature: 0.9},
+			wantMaxTokens:   10,
+			wantTemperature: 0.9,
+		},
+		{
+			name:          "explicit zero temperature",
+			req:           zero,
+			wantMaxTokens: 64,
+		},
+		{
+			name:            "unregistered model",
+			req:             &model.AIRequest{Model: "distilgpt2", Prompt: "Hello"},
+			wantMaxTokens:   100,
+			wantTemperature: 0.7,
+		},
+		{
+			name:    "context length exceeded",
+			req:     &model.AIRequest{Model: "gpt2", Prompt: strings.Repeat("word ", 40)},
+			wantErr: "exceeds the context length of gpt2 (100 tokens)",
+		},
+		{
+			name:    "max_tokens exceeds context length",
+			req:     &model.AIRequest{Model: "gpt2", Prompt: "Hello", MaxTokens: 200},
+			wantErr: "exceeds the context length of gpt2 (100 tokens)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockAIService()
+			var got *model.AIRequest
+			mock.GenerateTextFunc = func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+				got = req
+				return &model.AIResponse{Model: req.Model}, nil
+			}
+
+			s := NewDefaultsService(mock, &config.ModelsConfig{
+				Models: []config.ModelConfig{{
+					ID:               "gpt2",
+					Task:             "text-generation",
+					MaxContextLength: 100,
+					Parameters:       map[string]interface{}{"max_tokens": float64(64), "temperature": 0.5},
+				}},
+			}, &config.HuggingFaceConfig{MaxTokens: 100, Temperature: 0.7}, nil)
+
+			_, err := s.GenerateText(context.Background(), tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GenerateText() error = %v, want %q", err, tt.wantErr)
+				}
+				if mock.GenerateTextCalls != 0 {
+					t.Errorf("GenerateText() reached the service %d times, want 0", mock.GenerateTextCalls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateText() unexpected error = %v", err)
+			}
+			if got.MaxTokens != tt.wantMaxTokens || got.Temperature != tt.wantTemperature || !got.HasTemperature() {
+				t.Errorf("GenerateText() sent max_tokens %d, temperature %v (set %v), want %d, %v",
+					got.MaxTokens, got.Temperature, got.HasTemperature(), tt.wantMaxTokens, tt.wantTemperature)
+			}
+			if got == tt.req {
+				t.Error("GenerateText() modified the caller's request")
+			}
+		})
+	}
+}
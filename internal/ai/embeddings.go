@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// parseEmbeddings decodes a feature-extraction response for n inputs into one
+// vector per input. Sentence-embedding models return pooled vectors
+// ([n][dim]); plain encoders return token-level vectors ([n][tokens][dim]),
+// which are pooled with the given strategy. A single input may also come
+// back without the batch dimension.
+func parseEmbeddings(data []byte, n int, pooling string) ([][]float64, error) {
+	var pooled [][]float64
+	if err := json.Unmarshal(data, &pooled); err == nil {
+		if len(pooled) == n {
+			return pooled, nil
+		}
+		if n == 1 && len(pooled) > 0 {
+			// Token-level output for a single input without the batch dimension
+			return [][]float64{pool(pooled, pooling)}, nil
+		}
+		return nil, fmt.Errorf("expected %d embeddings, got %d", n, len(pooled))
+	}
+
+	var tokens [][][]float64
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings response: %w", err)
+	}
+	if len(tokens) != n {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", n, len(tokens))
+	}
+
+	vectors := make([][]float64, n)
+	for i, t := range tokens {
+		if len(t) == 0 {
+			return nil, fmt.Errorf("embedding %d has no tokens", i)
+		}
+		vectors[i] = pool(t, pooling)
+	}
+	return vectors, nil
+}
+
+// pool reduces token vectors to a single vector
+func pool(tokens [][]float64, pooling string) []float64 {
+	if pooling == model.PoolingCLS {
+		return tokens[0]
+	}
+
+	mean := make([]float64, len(tokens[0]))
+	for _, token := range tokens {
+		for i := range mean {
+			if i < len(token) {
+				mean[i] += token[i]
+			}
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(tokens))
+	}
+	return mean
+}
+
+// normalizeL2 scales v in place to unit length. Zero vectors are left as is.
+func normalizeL2(v []float64) {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= norm
+	}
+}
@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+func TestParseEmbeddings(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		n       int
+		pooling string
+		want    [][]float64
+		wantErr bool
+	}{
+		{
+			name: "pooled batch",
+			data: `[[1, 2], [3, 4]]`,
+			n:    2,
+			want: [][]float64{{1, 2}, {3, 4}},
+		},
+		{
+			name: "token-level batch with mean pooling",
+			data: `[[[1, 2], [3, 4]], [[5, 6]]]`,
+			n:    2,
+			want: [][]float64{{2, 3}, {5, 6}},
+		},
+		{
+			name:    "token-level batch with cls pooling",
+			data:    `[[[1, 2], [3, 4]]]`,
+			n:       1,
+			pooling: model.PoolingCLS,
+			want:    [][]float64{{1, 2}},
+		},
+		{
+			name: "token-level single input without batch dimension",
+			data: `[[1, 2], [3, 4], [5, 6]]`,
+			n:    1,
+			want: [][]float64{{3, 4}},
+		},
+		{
+			name:    "count mismatch",
+			data:    `[[1, 2], [3, 4], [5, 6]]`,
+			n:       2,
+			wantErr: true,
+		},
+		{
+			name:    "not embeddings",
+			data:    `{"error": "loading"}`,
+			n:       1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEmbeddings([]byte(tt.data), tt.n, tt.pooling)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseEmbeddings() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEmbeddings() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEmbeddings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeL2(t *testing.T) {
+	v := []float64{3, 4}
+	normalizeL2(v)
+	if math.Abs(v[0]-0.6) > 1e-9 || math.Abs(v[1]-0.8) > 1e-9 {
+		t.Errorf("normalizeL2() = %v, want [0.6 0.8]", v)
+	}
+
+	zero := []float64{0, 0}
+	normalizeL2(zero)
+	if zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("normalizeL2() of zero vector = %v, want [0 0]", zero)
+	}
+}
@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// Error types reported to clients for failed upstream requests and
+// operations a backend does not support
+const (
+	ErrorTypeBadRequest   = "upstream_bad_request"
+	ErrorTypeAuth         = "upstream_auth_error"
+	ErrorTypeNotFound     = "model_not_found"
+	ErrorTypeRateLimited  = "upstream_rate_limited"
+	ErrorTypeQuota        = "quota_exceeded"
+	ErrorTypeModelLoading = "model_loading"
+	ErrorTypeUnavailable  = "upstream_unavailable"
+	ErrorTypeTimeout      = "upstream_timeout"
+	ErrorTypeUnsupported  = "unsupported_operation"
+)
+
+// emptyModelNameError rejects model lookups without a model name
+func emptyModelNameError() error {
+	return &model.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "Model name cannot be empty",
+		Type:    "validation_error",
+	}
+}
+
+// UpstreamError is a failed request to an inference backend. StatusCode is
+// zero when no response was received, in which case Err holds the transport
+// error.
+type UpstreamError struct {
+	Model      string
+	StatusCode int
+	Message    string        // error reported by the backend
+	RetryAfter time.Duration // wait the backend asked for before retrying
+	Loading    bool          // the model is still being loaded
+	Timeout    bool
+	Err        error
+}
+
+// newUpstreamError builds the error of an unsuccessful response
+func newUpstreamError(modelName string, resp *http.Response, body []byte) *UpstreamError {
+	e := &UpstreamError{Model: modelName, StatusCode: resp.StatusCode}
+
+	var hfError HuggingFaceError
+	if json.Unmarshal(body, &hfError) == nil && hfError.Error != "" {
+		e.Message = hfError.Error
+		if hfError.Message != "" {
+			e.Message += " - " + hfError.Message
+		}
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	e.RetryAfter = requestedWait(resp.Header, hfError, time.Now())
+	e.Loading = resp.StatusCode == http.StatusServiceUnavailable &&
+		(hfError.EstimatedTime > 0 || strings.Contains(strings.ToLower(hfError.Error), "loading"))
+	e.Timeout = resp.StatusCode == http.StatusGatewayTimeout || resp.StatusCode == http.StatusRequestTimeout
+	return e
+}
+
+// transportError builds the error of a request that got no response
+func transportError(modelName string, err error) *UpstreamError {
+	var netErr net.Error
+	timeout := errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+	return &UpstreamError{Model: modelName, Timeout: timeout, Err: err}
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("HTTP request failed: %v", e.Err)
+	}
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether sending the request again may succeed
+func (e *UpstreamError) Retryable() bool {
+	return e.StatusCode == 0 || retryableStatus(e.StatusCode)
+}
+
+// response maps the failure to the status and type reported to clients
+func (e *UpstreamError) response() *model.ErrorResponse {
+	switch {
+	case e.Timeout:
+		return &model.ErrorResponse{Code: http.StatusGatewayTimeout, Type: ErrorTypeTimeout, Message: fmt.Sprintf("Model %s did not respond in time", e.Model)}
+	case e.StatusCode == 0:
+		return &model.ErrorResponse{Code: http.StatusServiceUnavailable, Type: ErrorTypeUnavailable, Message: fmt.Sprintf("Model %s could not be reached", e.Model)}
+	case e.Loading:
+		return &model.ErrorResponse{Code: http.StatusServiceUnavailable, Type: ErrorTypeModelLoading, Message: fmt.Sprintf("Model %s is loading", e.Model)}
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return &model.ErrorResponse{Code: http.StatusUnauthorized, Type: ErrorTypeAuth, Message: "The inference backend rejected the configured credentials"}
+	case e.StatusCode == http.StatusNotFound:
+		return &model.ErrorResponse{Code: http.StatusNotFound, Type: ErrorTypeNotFound, Message: fmt.Sprintf("Model %s was not found", e.Model)}
+	case e.StatusCode == http.StatusPaymentRequired:
+		return &model.ErrorResponse{Code: http.StatusTooManyRequests, Type: ErrorTypeQuota, Message: "The inference quota is exhausted"}
+	case e.StatusCode == http.StatusTooManyRequests:
+		return &model.ErrorResponse{Code: http.StatusTooManyRequests, Type: ErrorTypeRateLimited, Message: fmt.Sprintf("Model %s is rate limited", e.Model)}
+	case e.StatusCode >= http.StatusInternalServerError:
+		return &model.ErrorResponse{Code: http.StatusServiceUnavailable, Type: ErrorTypeUnavailable, Message: fmt.Sprintf("Model %s is unavailable", e.Model)}
+	}
+	return &model.ErrorResponse{Code: http.StatusBadRequest, Type: ErrorTypeBadRequest, Message: fmt.Sprintf("Model %s rejected the request", e.Model)}
+}
+
+// ErrorResponse converts an error returned by a service into the error
+// reported to clients. Errors the service reports as responses pass through,
+// upstream failures map to the status and type of their cause, deadlines to
+// 504, truncated streams to 502, and anything else to a 500 service_error
+// with the given message. The error text, which may quote the upstream
+// response, is only included in Details when details is set.
+func ErrorResponse(err error, message string, details bool) *model.ErrorResponse {
+	var errResp *model.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp
+	}
+
+	var upstream *UpstreamError
+	switch {
+	case errors.As(err, &upstream):
+		errResp = upstream.response()
+		if upstream.RetryAfter > 0 {
+			errResp.Details = fmt.Sprintf("retry in %ds", RetryAfterSeconds(err))
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		errResp = &model.ErrorResponse{Code: http.StatusGatewayTimeout, Type: ErrorTypeTimeout, Message: "The request timed out"}
+	case errors.Is(err, ErrStreamTruncated):
+		errResp = &model.ErrorResponse{Code: http.StatusBadGateway, Type: ErrorTypeUnavailable, Message: "The model's stream ended before the generation completed"}
+	default:
+		errResp = &model.ErrorResponse{Code: http.StatusInternalServerError, Type: "service_error", Message: message}
+	}
+	if details {
+		errResp.Details = err.Error()
+	}
+	return errResp
+}
+
+// RetryAfterSeconds returns the whole seconds an upstream failure asked to
+// wait before retrying, or 0 when it did not ask
+func RetryAfterSeconds(err error) int {
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || upstream.RetryAfter <= 0 {
+		return 0
+	}
+	return int(math.Ceil(upstream.RetryAfter.Seconds()))
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+func TestNewUpstreamError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		body          string
+		wantMessage   string
+		wantLoading   bool
+		wantRetry     time.Duration
+		wantRetryable bool
+	}{
+		{name: "error body", status: http.StatusBadRequest, body: `{"error": "Input is too long", "message": "max 512 tokens"}`, wantMessage: "Input is too long - max 512 tokens"},
+		{name: "raw body", status: http.StatusBadGateway, body: "bad gateway\n", wantMessage: "bad gateway", wantRetryable: true},
+		{name: "model loading", status: http.StatusServiceUnavailable, body: `{"error": "Model gpt2 is currently loading", "estimated_time": 20.0}`, wantMessage: "Model gpt2 is currently loading", wantLoading: true, wantRetry: 20 * time.Second, wantRetryable: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, retryAfter: "3", body: `{"error": "Rate limit reached"}`, wantMessage: "Rate limit reached", wantRetry: 3 * time.Second, wantRetryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := newUpstreamError("gpt2", resp, []byte(tt.body))
+			if err.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", err.Message, tt.wantMessage)
+			}
+			if err.Loading != tt.wantLoading {
+				t.Errorf("Loading = %v, want %v", err.Loading, tt.wantLoading)
+			}
+			if err.RetryAfter != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", err.RetryAfter, tt.wantRetry)
+			}
+			if got := err.Retryable(); got != tt.wantRetryable {
+				t.Errorf("Retryable() = %v, want %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	reported := &model.ErrorResponse{Code: http.StatusBadRequest, Message: "Prompt is required", Type: "validation_error"}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantType string
+	}{
+		{name: "reported by the service", err: fmt.Errorf("chunk 1 of 2: %w", reported), wantCode: http.StatusBadRequest, wantType: "validation_error"},
+		{name: "bad request", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusUnprocessableEntity}, wantCode: http.StatusBadRequest, wantType: ErrorTypeBadRequest},
+		{name: "unauthorized", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusForbidden}, wantCode: http.StatusUnauthorized, wantType: ErrorTypeAuth},
+		{name: "not found", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusNotFound}, wantCode: http.StatusNotFound, wantType: ErrorTypeNotFound},
+		{name: "quota", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusPaymentRequired}, wantCode: http.StatusTooManyRequests, wantType: ErrorTypeQuota},
+		{name: "rate limited", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusTooManyRequests}, wantCode: http.StatusTooManyRequests, wantType: ErrorTypeRateLimited},
+		{name: "model loading", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusServiceUnavailable, Loading: true}, wantCode: http.StatusServiceUnavailable, wantType: ErrorTypeModelLoading},
+		{name: "server error", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusInternalServerError}, wantCode: http.StatusServiceUnavailable, wantType: ErrorTypeUnavailable},
+		{name: "gateway timeout", err: &UpstreamError{Model: "gpt2", StatusCode: http.StatusGatewayTimeout, Timeout: true}, wantCode: http.StatusGatewayTimeout, wantType: ErrorTypeTimeout},
+		{name: "connection refused", err: transportError("gpt2", errors.New("connection refused")), wantCode: http.StatusServiceUnavailable, wantType: ErrorTypeUnavailable},
+		{name: "client timeout", err: transportError("gpt2", context.DeadlineExceeded), wantCode: http.StatusGatewayTimeout, wantType: ErrorTypeTimeout},
+		{name: "deadline", err: context.DeadlineExceeded, wantCode: http.StatusGatewayTimeout, wantType: ErrorTypeTimeout},
+		{name: "truncated stream", err: ErrStreamTruncated, wantCode: http.StatusBadGateway, wantType: ErrorTypeUnavailable},
+		{name: "unknown", err: errors.New("boom"), wantCode: http.StatusInternalServerError, wantType: "service_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ErrorResponse(tt.err, "Failed to generate text", false)
+			if got.Code != tt.wantCode || got.Type != tt.wantType {
+				t.Errorf("ErrorResponse() = %v/%v, want %v/%v", got.Code, got.Type, tt.wantCode, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestErrorResponseDetails(t *testing.T) {
+	err := &UpstreamError{Model: "gpt2", StatusCode: http.StatusBadRequest, Message: "secret upstream text"}
+
+	if got := ErrorResponse(err, "Failed to generate text", false); got.Details != nil {
+		t.Errorf("ErrorResponse() Details = %v, want none", got.Details)
+	}
+	if got := ErrorResponse(err, "Failed to generate text", true); got.Details != err.Error() {
+		t.Errorf("ErrorResponse() with details Details = %v, want %v", got.Details, err.Error())
+	}
+
+	loading := &UpstreamError{Model: "gpt2", StatusCode: http.StatusServiceUnavailable, Loading: true, RetryAfter: 1500 * time.Millisecond}
+	if got := ErrorResponse(loading, "Failed to generate text", false); got.Details != "retry in 2s" {
+		t.Errorf("ErrorResponse() Details = %v, want retry in 2s", got.Details)
+	}
+	if got := RetryAfterSeconds(fmt.Errorf("wrapped: %w", loading)); got != 2 {
+		t.Errorf("RetryAfterSeconds() = %v, want 2", got)
+	}
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// FallbackService decorates a model.AIService with fallback chains: when
+// text generation, sentiment analysis or summarization fails upstream, the
+// request is handed to the next model of the chain configured for its model,
+// or for its task. Responses report the model that served them.
+type FallbackService struct {
+	model.AIService
+	config  *config.FallbackConfig
+	tasks   *config.HuggingFaceConfig // default model of each task
+	metrics *metrics.Metrics
+	logger  logger.Logger
+}
+
+// NewFallbackService wraps service with the fallback chains of cfg
+func NewFallbackService(service model.AIService, cfg *config.FallbackConfig, tasks *config.HuggingFaceConfig, metrics *metrics.Metrics, logger logger.Logger) *FallbackService {
+	return &FallbackService{
+		AIService: service,
+		config:    cfg,
+		tasks:     tasks,
+		metrics:   metrics,
+		logger:    logger,
+	}
+}
+
+// GenerateText implements model.AIService
+func (s *FallbackService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return withFallbacks(ctx, s, req.Model, s.config.Chain(req.Model), func(fallback string) (*model.AIResponse, error) {
+		return s.AIService.GenerateText(ctx, withModel(req, fallback))
+	})
+}
+
+// GenerateCompletion implements model.AIService
+func (s *FallbackService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return withFallbacks(ctx, s, req.Model, s.config.Chain(req.Model), func(fallback string) (*model.AIResponse, error) {
+		return s.AIService.GenerateCompletion(ctx, withModel(req, fallback))
+	})
+}
+
+// GenerateTextStream implements model.AIService. A stream only falls back
+// while no token has reached the caller.
+func (s *FallbackService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	streamed := false
+	onChunk := func(chunk *model.StreamChunk) error {
+		streamed = true
+		return fn(chunk)
+	}
+
+	response, err := s.AIService.GenerateTextStream(ctx, req, onChunk)
+	failed := req.Model
+	for _, fallback := range s.config.Chain(req.Model) {
+		if err == nil || streamed || !canFallBack(ctx, err) {
+			break
+		}
+		if fallback == req.Model {
+			continue
+		}
+		s.fallingBack(ctx, req.Model, failed, fallback, err)
+		failed = fallback
+		response, err = s.AIService.GenerateTextStream(ctx, withModel(req, fallback), onChunk)
+	}
+	return response, err
+}
+
+// AnalyzeSentiment implements model.AIService
+func (s *FallbackService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+	primary := taskModel(req.Model, s.tasks.SentimentModel)
+	return withFallbacks(ctx, s, primary, s.taskChain(primary, s.config.Sentiment), func(fallback string) (*model.SentimentResponse, error) {
+		if fallback == "" {
+			return s.AIService.AnalyzeSentiment(ctx, req)
+		}
+		next := *req
+		next.Model = fallback
+		return s.AIService.AnalyzeSentiment(ctx, &next)
+	})
+}
+
+// SummarizeText implements model.AIService
+func (s *FallbackService) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+	primary := taskModel(req.Model, s.tasks.SummarizationModel)
+	return withFallbacks(ctx, s, primary, s.taskChain(primary, s.config.Summarization), func(fallback string) (*model.SummaryResponse, error) {
+		if fallback == "" {
+			return s.AIService.SummarizeText(ctx, req)
+		}
+		next := *req
+		next.Model = fallback
+		return s.AIService.SummarizeText(ctx, &next)
+	})
+}
+
+// taskChain returns the chain of the model, or the chain of its task when
+// the model has none
+func (s *FallbackService) taskChain(modelName string, task []string) []string {
+	if chain := s.config.Chain(modelName); chain != nil {
+		return chain
+	}
+	return task
+}
+
+// withFallbacks calls the service with the requested model and then, while
+// the failure is one another model may not share, with each model of chain
+// in turn. call receives the fallback model, or "" for the requested one.
+func withFallbacks[T any](ctx context.Context, s *FallbackService, primary string, chain []string, call func(fallback string) (T, error)) (T, error) {
+	result, err := call("")
+	failed := primary
+	for _, fallback := range chain {
+		if err == nil || !canFallBack(ctx, err) {
+			break
+		}
+		if fallback == primary {
+			continue
+		}
+		s.fallingBack(ctx, primary, failed, fallback, err)
+		failed = fallback
+		result, err = call(fallback)
+	}
+	return result, err
+}
+
+// fallingBack records that the request for primary moves on from the failed
+// model to fallback
+func (s *FallbackService) fallingBack(ctx context.Context, primary, failed, fallback string, err error) {
+	s.metrics.IncFallback(failed, fallback)
+	s.logger.Warn(ctx, "Model failed, falling back", map[string]interface{}{
+		"model":    primary,
+		"failed":   failed,
+		"fallback": fallback,
+		"error":    err.Error(),
+	})
+}
+
+// canFallBack reports whether another model may serve a request that failed
+// with err: the upstream was unavailable, overloaded, timed out or does not
+// have the model, or the model's circuit breaker is open. Rejected inputs
+// would be rejected again, and a finished request has nobody to answer.
+func canFallBack(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		return upstream.Retryable() || upstream.Timeout || upstream.StatusCode == http.StatusNotFound
+	}
+	var errResp *model.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Type == "circuit_open"
+}
+
+// withModel returns req for the fallback model, or req itself without one
+func withModel(req *model.AIRequest, fallback string) *model.AIRequest {
+	if fallback == "" {
+		return req
+	}
+	next := *req
+	next.Model = fallback
+	return &next
+}
@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/cache"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func TestFallbackGenerateText(t *testing.T) {
+	unavailable := &UpstreamError{StatusCode: http.StatusServiceUnavailable}
+
+	tests := []struct {
+		name      string
+		failures  map[string]error
+		wantModel string
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "primary succeeds", wantModel: "gpt2-large", wantCalls: 1},
+		{name: "first fallback", failures: map[string]error{"gpt2-large": unavailable}, wantModel: "gpt2-medium", wantCalls: 2},
+		{name: "second fallback", failures: map[string]error{"gpt2-large": unavailable, "gpt2-medium": transportError("gpt2-medium", context.DeadlineExceeded)}, wantModel: "gpt2", wantCalls: 3},
+		{name: "open circuit breaker", failures: map[string]error{"gpt2-large": &model.ErrorResponse{Code: http.StatusServiceUnavailable, Type: "circuit_open"}}, wantModel: "gpt2-medium", wantCalls: 2},
+		{name: "rejected inputs", failures: map[string]error{"gpt2-large": &UpstreamError{StatusCode: http.StatusBadRequest}}, wantErr: true, wantCalls: 1},
+		{name: "unknown error", failures: map[string]error{"gpt2-large": errors.New("boom")}, wantErr: true, wantCalls: 1},
+		{name: "chain exhausted", failures: map[string]error{"gpt2-large": unavailable, "gpt2-medium": unavailable, "gpt2": unavailable}, wantErr: true, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockAIService()
+			generate := mock.GenerateTextFunc
+			mock.GenerateTextFunc = func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+				if err := tt.failures[req.Model]; err != nil {
+					return nil, err
+				}
+				return generate(ctx, req)
+			}
+
+			s := NewFallbackService(mock, &config.FallbackConfig{
+				Models: map[string]string{"gpt2-large": "gpt2-medium|gpt2"},
+			}, &config.HuggingFaceConfig{}, metrics.New(), logger.NewNoopLogger())
+
+			req := &model.AIRequest{Model: "gpt2-large", Prompt: "Hello"}
+			response, err := s.GenerateText(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && response.Model != tt.wantModel {
+				t.Errorf("GenerateText() model = %v, want %v", response.Model, tt.wantModel)
+			}
+			if mock.GenerateTextCalls != tt.wantCalls {
+				t.Errorf("GenerateText() called the service %v times, want %v", mock.GenerateTextCalls, tt.wantCalls)
+			}
+			if req.Model != "gpt2-large" {
+				t.Errorf("GenerateText() changed the request model to %v", req.Model)
+			}
+		})
+	}
+}
+
+func TestFallbackTaskChains(t *testing.T) {
+	mock := mocks.NewMockAIService()
+	sentiment := mock.AnalyzeSentimentFunc
+	mock.AnalyzeSentimentFunc = func(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+		if req.Model == "" || req.Model == "custom/sentiment" {
+			return nil, &UpstreamError{StatusCode: http.StatusServiceUnavailable, Loading: true}
+		}
+		return sentiment(ctx, req)
+	}
+
+	m := metrics.New()
+	s := NewFallbackService(mock, &config.FallbackConfig{
+		Models:    map[string]string{"custom/*": "custom/backup"},
+		Sentiment: []string{"distilbert/sst2"},
+	}, &config.HuggingFaceConfig{SentimentModel: "cardiffnlp/roberta"}, m, logger.NewNoopLogger())
+
+	tests := []struct {
+		name      string
+		model     string
+		wantModel string
+	}{
+		{name: "default model", wantModel: "distilbert/sst2"},
+		{name: "model chain before task chain", model: "custom/sentiment", wantModel: "custom/backup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := s.AnalyzeSentiment(context.Background(), &model.SentimentRequest{Text: "I love it", Model: tt.model})
+			if err != nil {
+				t.Fatalf("AnalyzeSentiment() unexpected error = %v", err)
+			}
+			if response.Model != tt.wantModel {
+				t.Errorf("AnalyzeSentiment() model = %v, want %v", response.Model, tt.wantModel)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+	if want := `model_fallbacks_total{model="cardiffnlp/roberta",fallback="distilbert/sst2"} 1`; !strings.Contains(buf.String(), want) {
+		t.Errorf("metrics missing %q", want)
+	}
+}
+
+func TestFallbackAboveCache(t *testing.T) {
+	primaryDown := true
+	mock := mocks.NewMockAIService()
+	generate := mock.GenerateTextFunc
+	mock.GenerateTextFunc = func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+		if req.Model == "gpt2-large" && primaryDown {
+			return nil, &UpstreamError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return generate(ctx, req)
+	}
+
+	cached := NewCachedService(mock, cache.NewLRU(10), time.Minute, metrics.New(), logger.NewNoopLogger())
+	s := NewFallbackService(cached, &config.FallbackConfig{
+		Models: map[string]string{"gpt2-large": "gpt2-medium"},
+	}, &config.HuggingFaceConfig{}, metrics.New(), logger.NewNoopLogger())
+
+	generateText := func(wantModel string) {
+		t.Helper()
+		response, err := s.GenerateText(context.Background(), &model.AIRequest{Model: "gpt2-large", Prompt: "Hello"})
+		if err != nil {
+			t.Fatalf("GenerateText() unexpected error = %v", err)
+		}
+		if response.Model != wantModel {
+			t.Errorf("GenerateText() model = %v, want %v", response.Model, wantModel)
+		}
+	}
+
+	generateText("gpt2-medium")
+	// Once the primary has recovered its own response is served, not the
+	// fallback's response cached for the same request
+	primaryDown = false
+	generateText("gpt2-large")
+	generateText("gpt2-large")
+	if mock.GenerateTextCalls != 3 {
+		t.Errorf("GenerateText() called the service %v times, want 3", mock.GenerateTextCalls)
+	}
+}
+
+func TestFallbackStreamAfterTokens(t *testing.T) {
+	mock := mocks.NewMockAIService()
+	mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+		if err := fn(&model.StreamChunk{Text: "Hel"}); err != nil {
+			return nil, err
+		}
+		return nil, &UpstreamError{StatusCode: http.StatusBadGateway}
+	}
+
+	s := NewFallbackService(mock, &config.FallbackConfig{
+		Models: map[string]string{"gpt2-large": "gpt2"},
+	}, &config.HuggingFaceConfig{}, metrics.New(), logger.NewNoopLogger())
+
+	_, err := s.GenerateTextStream(context.Background(), &model.AIRequest{Model: "gpt2-large", Prompt: "Hello"}, func(*model.StreamChunk) error { return nil })
+	if err == nil {
+		t.Fatal("GenerateTextStream() expected error but got nil")
+	}
+	if mock.GenerateTextStreamCalls != 1 {
+		t.Errorf("GenerateTextStream() called the service %v times after streaming tokens, want 1", mock.GenerateTextStreamCalls)
+	}
+}
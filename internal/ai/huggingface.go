@@ -11,22 +11,31 @@ import (
 	"time"
 
 	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
 	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
 	"github.com/tusharr/go-ai-huggingface/pkg/logger"
 )
 
 // HuggingFaceService implements the AIService interface using Hugging Face API
 type HuggingFaceService struct {
-	config     *config.HuggingFaceConfig
-	httpClient *http.Client
-	logger     logger.Logger
+	config       *config.HuggingFaceConfig
+	httpClient   *http.Client
+	streamClient *http.Client
+	metrics      *metrics.Metrics
+	logger       logger.Logger
+	catalog      *catalog
+	tokens       *tokenizer.Counter
+	breakers     *circuitBreakers
+	flights      *flightGroup
 }
 
 // HuggingFaceRequest represents a request to Hugging Face API
 type HuggingFaceRequest struct {
-	Inputs     string                 `json:"inputs"`
+	Inputs     interface{}            `json:"inputs"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
 	Options    map[string]interface{} `json:"options,omitempty"`
+	Stream     bool                   `json:"stream,omitempty"`
 }
 
 // HuggingFaceResponse represents a response from Hugging Face API
@@ -39,24 +48,48 @@ type HuggingFaceResponse struct {
 
 // HuggingFaceError represents an error response from Hugging Face API
 type HuggingFaceError struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error         string  `json:"error"`
+	Message       string  `json:"message,omitempty"`
+	EstimatedTime float64 `json:"estimated_time,omitempty"` // seconds until a loading model is ready
 }
 
 // NewHuggingFaceService creates a new Hugging Face service instance
-func NewHuggingFaceService(config *config.HuggingFaceConfig, logger logger.Logger) *HuggingFaceService {
+func NewHuggingFaceService(config *config.HuggingFaceConfig, tokens *tokenizer.Counter, metrics *metrics.Metrics, logger logger.Logger) *HuggingFaceService {
 	return &HuggingFaceService{
 		config: config,
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
 		},
-		logger: logger,
+		// Streams may legitimately outlive config.Timeout; they are bounded
+		// by the request context instead
+		streamClient: &http.Client{},
+		metrics:      metrics,
+		logger:       logger,
+		catalog:      newCatalog(config.ModelCatalogFile, config.ModelInfoTTL),
+		tokens:       tokens,
+		breakers:     newCircuitBreakers(config.CircuitBreaker, metrics),
+		flights:      newFlightGroup(),
 	}
 }
 
+// LimitModels restricts circuit breakers to the models known reports, so
+// that requests naming arbitrary models do not each create one. Other models
+// are sent without a breaker, and their model catalog entries are the first
+// evicted. It must be called before the service is used.
+func (s *HuggingFaceService) LimitModels(known func(modelName string) bool) {
+	s.breakers.covered = known
+	s.catalog.known = known
+}
+
+// CircuitBreakers reports the circuit breaker of every model the service has
+// sent requests to
+func (s *HuggingFaceService) CircuitBreakers() []model.CircuitBreakerStatus {
+	return s.breakers.statuses()
+}
+
 // GenerateText generates text using the specified model
 func (s *HuggingFaceService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
 		return nil, err
 	}
 
@@ -67,22 +100,7 @@ func (s *HuggingFaceService) GenerateText(ctx context.Context, req *model.AIRequ
 		"prompt":     req.Prompt[:min(len(req.Prompt), 100)] + "...", // Log first 100 chars
 	})
 
-	hfReq := &HuggingFaceRequest{
-		Inputs: req.Prompt,
-		Parameters: map[string]interface{}{
-			"max_new_tokens": req.MaxTokens,
-			"temperature":    req.Temperature,
-			"top_p":          req.TopP,
-		},
-		Options: map[string]interface{}{
-			"wait_for_model": true,
-		},
-	}
-
-	// Merge additional parameters
-	for k, v := range req.Parameters {
-		hfReq.Parameters[k] = v
-	}
+	hfReq := newGenerationRequest(req)
 
 	response, err := s.makeRequest(ctx, req.Model, hfReq)
 	if err != nil {
@@ -114,7 +132,7 @@ func (s *HuggingFaceService) GenerateText(ctx context.Context, req *model.AIRequ
 		Choices:      make([]model.Choice, len(hfResponses)),
 	}
 
-	totalTokens := 0
+	completions := make([]string, len(hfResponses))
 	for i, hfResp := range hfResponses {
 		generatedText := hfResp.GeneratedText
 		// Remove original prompt from generated text if it's included
@@ -125,21 +143,17 @@ func (s *HuggingFaceService) GenerateText(ctx context.Context, req *model.AIRequ
 			Text:         generatedText,
 			FinishReason: "stop",
 		}
-
-		// Rough token estimation (1 token ≈ 4 characters)
-		totalTokens += (len(req.Prompt) + len(generatedText)) / 4
+		completions[i] = generatedText
 	}
 
-	aiResponse.Usage = model.Usage{
-		PromptTokens:     len(req.Prompt) / 4,
-		CompletionTokens: totalTokens - (len(req.Prompt) / 4),
-		TotalTokens:      totalTokens,
-	}
+	aiResponse.Usage = s.tokens.Usage(req.Model, req.Prompt, completions...)
+
+	s.metrics.AddTokens(req.Model, aiResponse.Usage.PromptTokens, aiResponse.Usage.CompletionTokens)
 
 	s.logger.Info(ctx, "Text generation completed", map[string]interface{}{
 		"request_id":    req.ID,
 		"processing_ms": processingTime.Milliseconds(),
-		"total_tokens":  totalTokens,
+		"total_tokens":  aiResponse.Usage.TotalTokens,
 	})
 
 	return aiResponse, nil
@@ -150,18 +164,72 @@ func (s *HuggingFaceService) GenerateCompletion(ctx context.Context, req *model.
 	return s.GenerateText(ctx, req)
 }
 
+// GenerateTextStream generates text using the specified model and invokes fn
+// for every token as it is produced by Hugging Face
+func (s *HuggingFaceService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	s.logger.Info(ctx, "Starting streaming text generation", map[string]interface{}{
+		"request_id": req.ID,
+		"model":      req.Model,
+	})
+
+	hfReq := newGenerationRequest(req)
+	hfReq.Stream = true
+
+	body, err := s.openStream(ctx, req.Model, hfReq)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to start text stream", map[string]interface{}{
+			"request_id": req.ID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+	defer body.Close()
+
+	result, err := readGenerationStream(ctx, body, req, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	processingTime := time.Since(startTime)
+	response := result.response(req, s.tokens.CountInput(req.Model, req.Prompt), processingTime)
+	s.metrics.AddTokens(req.Model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+
+	s.logger.Info(ctx, "Streaming text generation completed", map[string]interface{}{
+		"request_id":    req.ID,
+		"processing_ms": processingTime.Milliseconds(),
+		"tokens":        response.Usage.CompletionTokens,
+	})
+
+	return response, nil
+}
+
 // AnalyzeSentiment analyzes sentiment of the given text
-func (s *HuggingFaceService) AnalyzeSentiment(ctx context.Context, text string) (*model.SentimentResponse, error) {
+func (s *HuggingFaceService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	modelName := taskModel(req.Model, s.config.SentimentModel)
+	if req.Model != "" {
+		if err := s.checkTask(ctx, modelName, taskSentiment); err != nil {
+			return nil, err
+		}
+	}
+
 	s.logger.Info(ctx, "Starting sentiment analysis", map[string]interface{}{
-		"text_length": len(text),
+		"model":       modelName,
+		"text_length": len(req.Text),
 	})
 
 	hfReq := &HuggingFaceRequest{
-		Inputs: text,
+		Inputs: req.Text,
 	}
 
-	// Use a sentiment analysis model
-	modelName := "cardiffnlp/twitter-roberta-base-sentiment-latest"
 	response, err := s.makeRequest(ctx, modelName, hfReq)
 	if err != nil {
 		return nil, err
@@ -185,89 +253,210 @@ func (s *HuggingFaceService) AnalyzeSentiment(ctx context.Context, text string)
 	}
 
 	return &model.SentimentResponse{
-		Text:       text,
+		Text:       req.Text,
 		Sentiment:  bestResult.Label,
 		Score:      bestResult.Score,
 		Confidence: bestResult.Score,
+		Model:      modelName,
 	}, nil
 }
 
-// SummarizeText summarizes the given text
-func (s *HuggingFaceService) SummarizeText(ctx context.Context, text string, maxLength int) (*model.SummaryResponse, error) {
-	s.logger.Info(ctx, "Starting text summarization", map[string]interface{}{
-		"text_length": len(text),
-		"max_length":  maxLength,
+// Embed computes embeddings with a feature-extraction pipeline
+func (s *HuggingFaceService) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	s.logger.Info(ctx, "Starting embedding", map[string]interface{}{
+		"model":  req.Model,
+		"inputs": len(req.Input),
 	})
 
 	hfReq := &HuggingFaceRequest{
-		Inputs: text,
-		Parameters: map[string]interface{}{
-			"max_length": maxLength,
-			"min_length": maxLength / 4, // Min length is 25% of max
+		Inputs: []string(req.Input),
+		Options: map[string]interface{}{
+			"wait_for_model": true,
 		},
 	}
 
-	// Use a summarization model
-	modelName := "facebook/bart-large-cnn"
-	response, err := s.makeRequest(ctx, modelName, hfReq)
+	response, err := s.makeRequest(ctx, req.Model, hfReq)
 	if err != nil {
 		return nil, err
 	}
 
-	var hfResponses []HuggingFaceResponse
-	if err := json.Unmarshal(response, &hfResponses); err != nil {
-		return nil, fmt.Errorf("failed to parse summarization response: %w", err)
+	vectors, err := parseEmbeddings(response, len(req.Input), req.Pooling)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(hfResponses) == 0 {
-		return nil, fmt.Errorf("no summarization result")
+	promptTokens := 0
+	data := make([]model.Embedding, len(vectors))
+	for i, vector := range vectors {
+		if req.Normalize {
+			normalizeL2(vector)
+		}
+		data[i] = model.Embedding{Object: "embedding", Index: i, Embedding: vector}
+		promptTokens += s.tokens.CountInput(req.Model, req.Input[i])
 	}
 
-	summary := hfResponses[0].SummaryText
-	compression := float64(len(summary)) / float64(len(text))
+	s.metrics.AddTokens(req.Model, promptTokens, 0)
 
-	return &model.SummaryResponse{
-		OriginalText: text,
-		Summary:      summary,
-		Compression:  compression,
+	return &model.EmbeddingResponse{
+		Object:       "list",
+		Data:         data,
+		Model:        req.Model,
+		Usage:        model.Usage{PromptTokens: promptTokens, TotalTokens: promptTokens},
+		ProcessingMs: time.Since(startTime).Milliseconds(),
 	}, nil
 }
 
-// ValidateModel validates if the model is supported
-func (s *HuggingFaceService) ValidateModel(modelName string) error {
+// ValidateModel checks that the model exists on the Hub and returns its
+// metadata, falling back to the local model catalog when the Hub cannot be
+// reached
+func (s *HuggingFaceService) ValidateModel(ctx context.Context, modelName string) (*model.ModelInfo, error) {
 	if modelName == "" {
-		return fmt.Errorf("model name cannot be empty")
+		return nil, emptyModelNameError()
 	}
 
-	// Basic validation - in a real implementation, you might want to
-	// make a request to check if the model exists
-	supportedModels := []string{
-		"gpt2", "gpt2-medium", "gpt2-large", "gpt2-xl",
-		"microsoft/DialoGPT-medium", "microsoft/DialoGPT-large",
-		"facebook/bart-large-cnn", "cardiffnlp/twitter-roberta-base-sentiment-latest",
+	info, err := s.modelInfo(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Deployable {
+		s.logger.Warn(ctx, "Model is not deployed on the Inference API", map[string]interface{}{
+			"model": modelName,
+		})
 	}
+	return info, nil
+}
 
-	for _, supported := range supportedModels {
-		if modelName == supported {
-			return nil
-		}
+// newGenerationRequest builds the Hugging Face payload for a text generation request
+func newGenerationRequest(req *model.AIRequest) *HuggingFaceRequest {
+	hfReq := &HuggingFaceRequest{
+		Inputs: req.Prompt,
+		Parameters: map[string]interface{}{
+			"max_new_tokens": req.MaxTokens,
+			"temperature":    req.Temperature,
+			"top_p":          req.TopP,
+		},
+		Options: map[string]interface{}{
+			"wait_for_model": true,
+		},
 	}
 
-	// Allow any model name for flexibility
-	s.logger.Warn(context.Background(), "Using unvalidated model", map[string]interface{}{
-		"model": modelName,
-	})
+	// Merge additional parameters
+	for k, v := range req.Parameters {
+		hfReq.Parameters[k] = v
+	}
 
-	return nil
+	return hfReq
 }
 
-// makeRequest makes an HTTP request to Hugging Face API
+// openStream starts a streaming request to Hugging Face API and returns the
+// response body once the upstream has accepted it. Failures are retried like
+// in makeRequest until tokens flow; the stream is not restarted afterwards.
+func (s *HuggingFaceService) openStream(ctx context.Context, modelName string, req *HuggingFaceRequest) (io.ReadCloser, error) {
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/models/%s", s.config.BaseURL, modelName)
+
+	policy := s.config.RetryPolicy(modelName)
+	var lastErr error
+	var requested time.Duration // wait asked for by the last response
+	for attempt := 0; attempt <= policy.Attempts; attempt++ {
+		if attempt > 0 {
+			wait := retryWait(policy, attempt, requested)
+			ok, err := sleepRetry(ctx, wait)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// The request deadline would pass before the retry
+				break
+			}
+			s.metrics.IncRetry(modelName)
+			s.logger.Info(ctx, "Retrying stream request", map[string]interface{}{
+				"attempt": attempt,
+				"model":   modelName,
+				"wait_ms": wait.Milliseconds(),
+			})
+		}
+		requested = 0
+
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+s.config.APIKey)
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Accept", "text/event-stream")
+		httpReq.Header.Set("User-Agent", "go-ai-huggingface/1.0")
+
+		// Allowed last, so that every request let through reaches record
+		if err := s.breakers.allow(modelName); err != nil {
+			return nil, err
+		}
+
+		attemptStart := time.Now()
+		resp, err := s.streamClient.Do(httpReq)
+		if err != nil {
+			s.metrics.ObserveUpstream(modelName, 0, time.Since(attemptStart))
+			s.breakers.record(modelName, upstreamResult(ctx.Err(), 0))
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = transportError(modelName, err)
+			continue
+		}
+		s.metrics.ObserveUpstream(modelName, resp.StatusCode, time.Since(attemptStart))
+		s.breakers.record(modelName, upstreamResult(nil, resp.StatusCode))
+
+		if resp.StatusCode == http.StatusOK {
+			return resp.Body, nil
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		upstreamErr := newUpstreamError(modelName, resp, body)
+		lastErr = upstreamErr
+		if !upstreamErr.Retryable() {
+			break
+		}
+		requested = upstreamErr.RetryAfter
+	}
+
+	return nil, lastErr
+}
+
+// makeRequest makes an HTTP request to Hugging Face API. Identical requests
+// for the same model in flight at the same time share one upstream call,
+// unless they sample: every caller is owed its own sampled output.
 func (s *HuggingFaceService) makeRequest(ctx context.Context, modelName string, req *HuggingFaceRequest) ([]byte, error) {
 	requestBody, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	if !s.config.CoalesceRequests || samples(req.Parameters) {
+		return s.send(ctx, modelName, requestBody)
+	}
+	body, shared, err := s.flights.do(ctx, modelName+"\x00"+string(requestBody), func(ctx context.Context) ([]byte, error) {
+		return s.send(ctx, modelName, requestBody)
+	})
+	if shared {
+		s.metrics.IncCoalesced(modelName)
+	}
+	return body, err
+}
+
+// send posts a request body to the model, retrying connection failures, rate
+// limits and server errors with the retry policy of the model
+func (s *HuggingFaceService) send(ctx context.Context, modelName string, requestBody []byte) ([]byte, error) {
 	url := fmt.Sprintf("%s/models/%s", s.config.BaseURL, modelName)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
 	if err != nil {
@@ -279,19 +468,28 @@ func (s *HuggingFaceService) makeRequest(ctx context.Context, modelName string,
 	httpReq.Header.Set("User-Agent", "go-ai-huggingface/1.0")
 
 	// Retry logic
+	policy := s.config.RetryPolicy(modelName)
 	var lastErr error
-	for attempt := 0; attempt <= s.config.RetryAttempts; attempt++ {
+	var requested time.Duration // wait asked for by the last response
+	for attempt := 0; attempt <= policy.Attempts; attempt++ {
 		if attempt > 0 {
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(s.config.RetryDelay):
+			wait := retryWait(policy, attempt, requested)
+			ok, err := sleepRetry(ctx, wait)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// The request deadline would pass before the retry
+				break
 			}
+			s.metrics.IncRetry(modelName)
 			s.logger.Info(ctx, "Retrying request", map[string]interface{}{
 				"attempt": attempt,
 				"model":   modelName,
+				"wait_ms": wait.Milliseconds(),
 			})
 		}
+		requested = 0
 
 		// Recreate the request body for each attempt as it's consumed by Do()
 		httpReq, err = http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
@@ -302,35 +500,41 @@ func (s *HuggingFaceService) makeRequest(ctx context.Context, modelName string,
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("User-Agent", "go-ai-huggingface/1.0")
 
+		// Fail fast, without retrying, while the model's breaker is open.
+		// Allowed last, so that every request let through reaches record.
+		if err := s.breakers.allow(modelName); err != nil {
+			return nil, err
+		}
+
+		attemptStart := time.Now()
 		resp, err := s.httpClient.Do(httpReq)
 		if err != nil {
-			lastErr = fmt.Errorf("HTTP request failed: %w", err)
+			s.metrics.ObserveUpstream(modelName, 0, time.Since(attemptStart))
+			s.breakers.record(modelName, upstreamResult(ctx.Err(), 0))
+			lastErr = transportError(modelName, err)
 			continue
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close() // Close immediately to avoid leaks
+		s.metrics.ObserveUpstream(modelName, resp.StatusCode, time.Since(attemptStart))
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response: %w", err)
+			s.breakers.record(modelName, upstreamResult(ctx.Err(), 0))
+			lastErr = transportError(modelName, fmt.Errorf("failed to read response: %w", err))
 			continue
 		}
+		s.breakers.record(modelName, upstreamResult(nil, resp.StatusCode))
 
 		if resp.StatusCode == http.StatusOK {
 			return body, nil
 		}
 
-		// Handle error response
-		var hfError HuggingFaceError
-		if json.Unmarshal(body, &hfError) == nil {
-			lastErr = fmt.Errorf("API error (%d): %s - %s", resp.StatusCode, hfError.Error, hfError.Message)
-		} else {
-			lastErr = fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
-		}
-
-		// Don't retry on client errors (4xx)
-		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		upstreamErr := newUpstreamError(modelName, resp, body)
+		lastErr = upstreamErr
+		if !upstreamErr.Retryable() {
 			break
 		}
+		requested = upstreamErr.RetryAfter
 	}
 
 	return nil, lastErr
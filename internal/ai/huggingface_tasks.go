@@ -0,0 +1,313 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// zeroShotResponse is the output of the zero-shot-classification pipeline
+type zeroShotResponse struct {
+	Sequence string    `json:"sequence"`
+	Labels   []string  `json:"labels"`
+	Scores   []float64 `json:"scores"`
+}
+
+// ClassifyZeroShot scores the text against arbitrary candidate labels with a
+// zero-shot-classification (NLI) model
+func (s *HuggingFaceService) ClassifyZeroShot(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	modelName := taskModel(req.Model, s.config.ZeroShotModel)
+	if req.Model != "" {
+		if err := s.checkTask(ctx, modelName, taskZeroShot); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info(ctx, "Starting zero-shot classification", map[string]interface{}{
+		"model":       modelName,
+		"text_length": len(req.Text),
+		"labels":      len(req.Labels),
+	})
+
+	parameters := map[string]interface{}{
+		"candidate_labels": req.Labels,
+		"multi_label":      req.MultiLabel,
+	}
+	if req.HypothesisTemplate != "" {
+		parameters["hypothesis_template"] = req.HypothesisTemplate
+	}
+	hfReq := &HuggingFaceRequest{
+		Inputs:     req.Text,
+		Parameters: parameters,
+		Options: map[string]interface{}{
+			"wait_for_model": true,
+		},
+	}
+
+	response, err := s.makeRequest(ctx, modelName, hfReq)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := parseZeroShot(response)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Labels) != len(result.Scores) {
+		return nil, fmt.Errorf("zero-shot response has %d labels but %d scores", len(result.Labels), len(result.Scores))
+	}
+
+	labels := make([]model.LabelScore, len(result.Labels))
+	for i, label := range result.Labels {
+		labels[i] = model.LabelScore{Label: label, Score: result.Scores[i]}
+	}
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].Score > labels[j].Score
+	})
+
+	return &model.ClassificationResponse{
+		Text:       req.Text,
+		Model:      modelName,
+		Labels:     labels,
+		MultiLabel: req.MultiLabel,
+	}, nil
+}
+
+// parseZeroShot accepts both the single-object and the one-element list form
+// of the zero-shot pipeline output
+func parseZeroShot(data []byte) (*zeroShotResponse, error) {
+	var single zeroShotResponse
+	if err := json.Unmarshal(data, &single); err == nil {
+		return &single, nil
+	}
+
+	var list []zeroShotResponse
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse zero-shot response: %w", err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no zero-shot classification result")
+	}
+	return &list[0], nil
+}
+
+// tokenClassification is a single entry of the token-classification pipeline
+// output. Aggregated output sets EntityGroup, raw token output sets Entity.
+type tokenClassification struct {
+	EntityGroup string  `json:"entity_group"`
+	Entity      string  `json:"entity"`
+	Score       float64 `json:"score"`
+	Word        string  `json:"word"`
+	Start       *int    `json:"start"`
+	End         *int    `json:"end"`
+}
+
+// ExtractEntities finds named entities with a token-classification model
+func (s *HuggingFaceService) ExtractEntities(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	modelName := taskModel(req.Model, s.config.NERModel)
+	if req.Model != "" {
+		if err := s.checkTask(ctx, modelName, taskNER); err != nil {
+			return nil, err
+		}
+	}
+	strategy := req.AggregationStrategy
+	if strategy == "" {
+		strategy = model.AggregationSimple
+	}
+
+	s.logger.Info(ctx, "Starting entity extraction", map[string]interface{}{
+		"model":       modelName,
+		"text_length": len(req.Text),
+		"aggregation": strategy,
+	})
+
+	hfReq := &HuggingFaceRequest{
+		Inputs: req.Text,
+		Parameters: map[string]interface{}{
+			"aggregation_strategy": strategy,
+		},
+		Options: map[string]interface{}{
+			"wait_for_model": true,
+		},
+	}
+
+	response, err := s.makeRequest(ctx, modelName, hfReq)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := parseTokenClassification(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.EntityResponse{
+		Text:     req.Text,
+		Model:    modelName,
+		Entities: toEntities(req.Text, tokens, req.MinScore),
+	}, nil
+}
+
+// parseTokenClassification accepts both the flat and the nested (batched)
+// form of the token-classification pipeline output
+func parseTokenClassification(data []byte) ([]tokenClassification, error) {
+	var flat []tokenClassification
+	if err := json.Unmarshal(data, &flat); err == nil {
+		return flat, nil
+	}
+
+	var nested [][]tokenClassification
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return nil, fmt.Errorf("failed to parse entity response: %w", err)
+	}
+	if len(nested) == 0 {
+		return nil, nil
+	}
+	return nested[0], nil
+}
+
+// toEntities converts pipeline output into entity spans sorted by position.
+// Offsets from the pipeline count characters, so the span text is cut from
+// the input by rune; the token's word is used when offsets are missing.
+func toEntities(text string, tokens []tokenClassification, minScore float64) []model.Entity {
+	runes := []rune(text)
+	entities := make([]model.Entity, 0, len(tokens))
+	for _, token := range tokens {
+		if token.Score < minScore {
+			continue
+		}
+
+		entity := model.Entity{
+			Type:  token.EntityGroup,
+			Text:  token.Word,
+			Start: -1,
+			End:   -1,
+			Score: token.Score,
+		}
+		if entity.Type == "" {
+			entity.Type = token.Entity
+		}
+		if token.Start != nil && token.End != nil && *token.Start >= 0 && *token.Start < *token.End && *token.End <= len(runes) {
+			entity.Start, entity.End = *token.Start, *token.End
+			entity.Text = string(runes[entity.Start:entity.End])
+		}
+		entities = append(entities, entity)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+	return entities
+}
+
+// qaResponse is the output of the question-answering pipeline. Start and End
+// are character offsets into the context the model was given.
+type qaResponse struct {
+	Answer string  `json:"answer"`
+	Score  float64 `json:"score"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+}
+
+// AnswerQuestion extracts the answer to question from contextText with a
+// question-answering model. Contexts longer than the configured window are
+// searched in overlapping windows and the highest scoring answer is returned.
+func (s *HuggingFaceService) AnswerQuestion(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+	modelName := s.config.QAModel
+	windows := overlappingWindows(contextText, s.config.QAWindowSize, s.config.QAWindowOverlap)
+
+	s.logger.Info(ctx, "Starting question answering", map[string]interface{}{
+		"model":          modelName,
+		"context_length": len(contextText),
+		"windows":        len(windows),
+	})
+
+	answers := make([]qaResponse, len(windows))
+	for i, window := range windows {
+		hfReq := &HuggingFaceRequest{
+			Inputs: map[string]string{
+				"question": question,
+				"context":  window.Text,
+			},
+			Options: map[string]interface{}{
+				"wait_for_model": true,
+			},
+		}
+
+		response, err := s.makeRequest(ctx, modelName, hfReq)
+		if err != nil {
+			return nil, fmt.Errorf("window %d of %d: %w", i+1, len(windows), err)
+		}
+
+		answer, err := parseQA(response)
+		if err != nil {
+			return nil, err
+		}
+		answers[i] = *answer
+	}
+
+	result := &model.QAResponse{
+		Question: question,
+		Start:    -1,
+		End:      -1,
+		Model:    modelName,
+		Windows:  len(windows),
+	}
+	if best, ok := bestAnswer(windows, answers); ok {
+		result.Answer = best.Answer
+		result.Start = best.Start
+		result.End = best.End
+		result.Score = best.Score
+	}
+	return result, nil
+}
+
+// parseQA accepts both the single-object and the list form of the
+// question-answering pipeline output, taking the top answer of a list
+func parseQA(data []byte) (*qaResponse, error) {
+	var single qaResponse
+	if err := json.Unmarshal(data, &single); err == nil {
+		return &single, nil
+	}
+
+	var list []qaResponse
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse question answering response: %w", err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no question answering result")
+	}
+	return &list[0], nil
+}
+
+// bestAnswer returns the highest scoring non-empty answer across windows with
+// its offsets shifted into the full context. It reports false when no window
+// produced an answer.
+func bestAnswer(windows []textWindow, answers []qaResponse) (qaResponse, bool) {
+	var best qaResponse
+	found := false
+	for i, answer := range answers {
+		if strings.TrimSpace(answer.Answer) == "" {
+			continue
+		}
+		if !found || answer.Score > best.Score {
+			best = answer
+			best.Start += windows[i].Offset
+			best.End += windows[i].Offset
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+func TestParseZeroShot(t *testing.T) {
+	want := &zeroShotResponse{Sequence: "text", Labels: []string{"a", "b"}, Scores: []float64{0.7, 0.3}}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "object", data: `{"sequence": "text", "labels": ["a", "b"], "scores": [0.7, 0.3]}`},
+		{name: "list", data: `[{"sequence": "text", "labels": ["a", "b"], "scores": [0.7, 0.3]}]`},
+		{name: "empty list", data: `[]`, wantErr: true},
+		{name: "invalid", data: `"nope"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseZeroShot([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseZeroShot() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseZeroShot() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parseZeroShot() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestToEntities(t *testing.T) {
+	text := "Zoë works at Acme in Paris"
+	data := `[
+		{"entity_group": "LOC", "score": 0.99, "word": "Paris", "start": 21, "end": 26},
+		{"entity_group": "PER", "score": 0.98, "word": "Zo ##ë", "start": 0, "end": 3},
+		{"entity_group": "ORG", "score": 0.40, "word": "Acme", "start": 13, "end": 17},
+		{"entity": "B-MISC", "score": 0.90, "word": "works"}
+	]`
+
+	tokens, err := parseTokenClassification([]byte(data))
+	if err != nil {
+		t.Fatalf("parseTokenClassification() unexpected error = %v", err)
+	}
+
+	got := toEntities(text, tokens, 0.5)
+	want := []model.Entity{
+		{Type: "B-MISC", Text: "works", Start: -1, End: -1, Score: 0.90},
+		{Type: "PER", Text: "Zoë", Start: 0, End: 3, Score: 0.98},
+		{Type: "LOC", Text: "Paris", Start: 21, End: 26, Score: 0.99},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toEntities() = %+v, want %+v", got, want)
+	}
+
+	nested, err := parseTokenClassification([]byte(`[[{"entity_group": "PER", "score": 0.9, "word": "Zoë", "start": 0, "end": 3}]]`))
+	if err != nil || len(nested) != 1 || nested[0].EntityGroup != "PER" {
+		t.Errorf("parseTokenClassification() nested = %+v, %v", nested, err)
+	}
+}
+
+func TestParseQA(t *testing.T) {
+	want := &qaResponse{Answer: "Ada", Score: 0.9, Start: 18, End: 21}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "object", data: `{"answer": "Ada", "score": 0.9, "start": 18, "end": 21}`},
+		{name: "list", data: `[{"answer": "Ada", "score": 0.9, "start": 18, "end": 21}, {"answer": "it", "score": 0.1, "start": 0, "end": 2}]`},
+		{name: "empty list", data: `[]`, wantErr: true},
+		{name: "invalid", data: `"nope"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQA([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseQA() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQA() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parseQA() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBestAnswer(t *testing.T) {
+	windows := []textWindow{{Offset: 0}, {Offset: 100}, {Offset: 200}}
+
+	got, ok := bestAnswer(windows, []qaResponse{
+		{Answer: "London", Score: 0.4, Start: 10, End: 16},
+		{Answer: "Paris", Score: 0.8, Start: 5, End: 10},
+		{Answer: "", Score: 0.95},
+	})
+	want := qaResponse{Answer: "Paris", Score: 0.8, Start: 105, End: 110}
+	if !ok || got != want {
+		t.Errorf("bestAnswer() = %+v, %v, want %+v, true", got, ok, want)
+	}
+
+	if _, ok := bestAnswer(windows[:1], []qaResponse{{Answer: " ", Score: 0.5}}); ok {
+		t.Errorf("bestAnswer() with no answers = true, want false")
+	}
+}
@@ -0,0 +1,201 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// OpenAIService implements text generation against an OpenAI-compatible
+// /completions endpoint (OpenAI, vLLM, llama.cpp server and similar)
+type OpenAIService struct {
+	unsupportedService
+	client  *providerClient
+	tokens  *tokenizer.Counter
+	metrics *metrics.Metrics
+	logger  logger.Logger
+}
+
+// OpenAICompletionRequest represents a request to the /completions endpoint
+type OpenAICompletionRequest struct {
+	Model       string   `json:"model"`
+	Prompt      string   `json:"prompt"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        float32  `json:"top_p,omitempty"`
+	Stream      bool     `json:"stream,omitempty"`
+}
+
+// OpenAICompletionResponse represents a response (or stream chunk) from the
+// /completions endpoint
+type OpenAICompletionResponse struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Index        int    `json:"index"`
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *model.Usage `json:"usage,omitempty"`
+}
+
+// NewOpenAIService creates a new OpenAI-compatible service instance. The
+// configured base URL should include the API version prefix, e.g.
+// https://api.openai.com/v1
+func NewOpenAIService(config *config.ProviderConfig, tokens *tokenizer.Counter, metrics *metrics.Metrics, logger logger.Logger) *OpenAIService {
+	return &OpenAIService{
+		unsupportedService: unsupportedService{provider: "openai"},
+		client:             newProviderClient(config, metrics),
+		tokens:             tokens,
+		metrics:            metrics,
+		logger:             logger,
+	}
+}
+
+// GenerateText generates text using the OpenAI-compatible endpoint
+func (s *OpenAIService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	s.logger.Info(ctx, "Starting OpenAI-compatible text generation", map[string]interface{}{
+		"request_id": req.ID,
+		"model":      req.Model,
+	})
+
+	body, err := s.client.post(ctx, "/completions", req.Model, newOpenAIRequest(req, false))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to generate text", map[string]interface{}{
+			"request_id": req.ID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	var oaResp OpenAICompletionResponse
+	if err := json.Unmarshal(body, &oaResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(oaResp.Choices) == 0 {
+		return nil, fmt.Errorf("no response generated")
+	}
+
+	aiResponse := &model.AIResponse{
+		ID:           req.ID,
+		Model:        req.Model,
+		GeneratedAt:  time.Now(),
+		ProcessingMs: time.Since(startTime).Milliseconds(),
+		Choices:      make([]model.Choice, len(oaResp.Choices)),
+	}
+	completions := make([]string, len(oaResp.Choices))
+	for i, choice := range oaResp.Choices {
+		aiResponse.Choices[i] = model.Choice{
+			Index:        choice.Index,
+			Text:         choice.Text,
+			FinishReason: choice.FinishReason,
+		}
+		completions[i] = choice.Text
+	}
+
+	if oaResp.Usage != nil {
+		aiResponse.Usage = *oaResp.Usage
+	} else {
+		aiResponse.Usage = s.tokens.Usage(req.Model, req.Prompt, completions...)
+	}
+	s.metrics.AddTokens(req.Model, aiResponse.Usage.PromptTokens, aiResponse.Usage.CompletionTokens)
+
+	return aiResponse, nil
+}
+
+// GenerateCompletion is an alias for GenerateText for compatibility
+func (s *OpenAIService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return s.GenerateText(ctx, req)
+}
+
+// GenerateTextStream streams tokens from the OpenAI-compatible endpoint
+func (s *OpenAIService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	body, err := s.client.stream(ctx, "/completions", req.Model, newOpenAIRequest(req, true))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to start text stream", map[string]interface{}{
+			"request_id": req.ID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+	defer body.Close()
+
+	result := &streamResult{finishReason: "stop"}
+	var usage *model.Usage
+	index := 0
+	finished := false
+
+	err = scanSSE(ctx, body, func(data string) error {
+		var chunk OpenAICompletionResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to parse stream event: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				result.finishReason = choice.FinishReason
+				finished = true
+			}
+			if choice.Text == "" {
+				continue
+			}
+			result.text.WriteString(choice.Text)
+			result.completionTokens++
+			if err := fn(&model.StreamChunk{
+				ID:    req.ID,
+				Model: req.Model,
+				Index: index,
+				Text:  choice.Text,
+			}); err != nil {
+				return err
+			}
+			index++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, ErrStreamTruncated
+	}
+
+	response := result.response(req, s.tokens.CountInput(req.Model, req.Prompt), time.Since(startTime))
+	if usage != nil {
+		response.Usage = *usage
+	}
+	s.metrics.AddTokens(req.Model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	return response, nil
+}
+
+// newOpenAIRequest builds an OpenAI completion payload. Temperature is always
+// sent because zero is meaningful (greedy decoding) for this API.
+func newOpenAIRequest(req *model.AIRequest, stream bool) *OpenAICompletionRequest {
+	temperature := req.Temperature
+	return &OpenAICompletionRequest{
+		Model:       req.Model,
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: &temperature,
+		TopP:        req.TopP,
+		Stream:      stream,
+	}
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+)
+
+// providerClient performs JSON requests against a self-hosted or third-party
+// inference backend
+type providerClient struct {
+	baseURL      string
+	apiKey       string
+	httpClient   *http.Client
+	streamClient *http.Client
+	metrics      *metrics.Metrics
+}
+
+func newProviderClient(cfg *config.ProviderConfig, metrics *metrics.Metrics) *providerClient {
+	return &providerClient{
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		streamClient: &http.Client{},
+		metrics:      metrics,
+	}
+}
+
+// post sends payload to path and returns the response body of a 200 response
+func (c *providerClient) post(ctx context.Context, path, modelName string, payload interface{}) ([]byte, error) {
+	resp, err := c.do(ctx, c.httpClient, path, modelName, payload, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// stream sends payload to path and returns the event stream body of a 200 response
+func (c *providerClient) stream(ctx context.Context, path, modelName string, payload interface{}) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, c.streamClient, path, modelName, payload, true)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *providerClient) do(ctx context.Context, client *http.Client, path, modelName string, payload interface{}, stream bool) (*http.Response, error) {
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "go-ai-huggingface/1.0")
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		c.metrics.ObserveUpstream(modelName, 0, time.Since(start))
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, transportError(modelName, err)
+	}
+	c.metrics.ObserveUpstream(modelName, resp.StatusCode, time.Since(start))
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, newUpstreamError(modelName, resp, body)
+	}
+
+	return resp, nil
+}
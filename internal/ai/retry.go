@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+)
+
+// retryableStatus reports whether a response is worth retrying: rate limits
+// and server errors, including models that are still loading. Other client
+// errors would fail again.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryWait returns how long to wait before retry number attempt, counted
+// from 1: the backoff of the policy, or the wait the API asked for when it is
+// longer, capped at MaxWait
+func retryWait(policy config.RetryConfig, attempt int, requested time.Duration) time.Duration {
+	return max(backoff(policy, attempt), min(requested, policy.MaxWait))
+}
+
+// backoff doubles Delay for every earlier retry up to MaxDelay, or keeps it
+// constant without a MaxDelay, and picks a random wait between half and all
+// of it so that clients retrying together spread out
+func backoff(policy config.RetryConfig, attempt int) time.Duration {
+	delay := policy.Delay
+	for i := 1; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)+1))
+}
+
+// requestedWait reads how long the API asked to wait before retrying: the
+// Retry-After header, in seconds or as a date, or the estimated loading time
+// of a model reported in the error body
+func requestedWait(header http.Header, hfError HuggingFaceError, now time.Time) time.Duration {
+	var wait time.Duration
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			wait = date.Sub(now)
+		}
+	}
+	if hfError.EstimatedTime > 0 {
+		wait = max(wait, time.Duration(hfError.EstimatedTime*float64(time.Second)))
+	}
+	return max(wait, 0)
+}
+
+// sleepRetry waits before a retry. It returns false right away when the
+// request deadline would pass before the retry is sent, and the context
+// error if the request ends while waiting.
+func sleepRetry(ctx context.Context, wait time.Duration) (bool, error) {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+		return false, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-timer.C:
+		return true, nil
+	}
+}
@@ -0,0 +1,172 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := config.RetryConfig{Delay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 3, want: 300 * time.Millisecond},
+		{attempt: 10, want: 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if got := backoff(policy, tt.attempt); got < tt.want/2 || got > tt.want {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.want/2, tt.want)
+			}
+		}
+	}
+
+	constant := config.RetryConfig{Delay: 100 * time.Millisecond}
+	if got := backoff(constant, 5); got > 100*time.Millisecond {
+		t.Errorf("backoff() without max delay = %v, want at most 100ms", got)
+	}
+}
+
+func TestRequestedWait(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		hfError    HuggingFaceError
+		want       time.Duration
+	}{
+		{name: "none", want: 0},
+		{name: "retry after seconds", retryAfter: "7", want: 7 * time.Second},
+		{name: "retry after date", retryAfter: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{name: "retry after past date", retryAfter: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "invalid retry after", retryAfter: "soon", want: 0},
+		{name: "model loading", hfError: HuggingFaceError{Error: "Model is loading", EstimatedTime: 20.5}, want: 20500 * time.Millisecond},
+		{name: "longest wait", retryAfter: "30", hfError: HuggingFaceError{EstimatedTime: 20}, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := requestedWait(header, tt.hfError, now); got != tt.want {
+				t.Errorf("requestedWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeRequestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter)
+		timeout      time.Duration
+		wantErr      bool
+		wantRequests int32
+		minElapsed   time.Duration
+		maxElapsed   time.Duration
+	}{
+		{
+			name: "model loading",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					http.Error(w, `{"error": "Model is loading", "estimated_time": 0.1}`, http.StatusServiceUnavailable)
+				},
+			},
+			wantRequests: 2,
+			minElapsed:   100 * time.Millisecond,
+		},
+		{
+			name: "rate limited",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "0")
+					http.Error(w, `{"error": "Rate limit reached"}`, http.StatusTooManyRequests)
+				},
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "client error",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					http.Error(w, `{"error": "Invalid inputs"}`, http.StatusBadRequest)
+				},
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name: "wait beyond the deadline",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "10")
+					http.Error(w, `{"error": "Rate limit reached"}`, http.StatusTooManyRequests)
+				},
+			},
+			timeout:      time.Second,
+			wantErr:      true,
+			wantRequests: 1,
+			maxElapsed:   500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1))
+				if n <= len(tt.responses) {
+					tt.responses[n-1](w)
+					return
+				}
+				w.Write([]byte(`[{"generated_text": "ok"}]`))
+			}))
+			defer server.Close()
+
+			s := NewHuggingFaceService(&config.HuggingFaceConfig{
+				BaseURL: server.URL,
+				Retry:   config.RetryConfig{Attempts: 3, Delay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxWait: time.Minute},
+			}, nil, metrics.New(), logger.NewNoopLogger())
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			_, err := s.makeRequest(ctx, "gpt2", &HuggingFaceRequest{Inputs: "hi"})
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("makeRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("makeRequest() sent %v requests, want %v", got, tt.wantRequests)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("makeRequest() took %v, want at least %v", elapsed, tt.minElapsed)
+			}
+			if tt.maxElapsed > 0 && elapsed > tt.maxElapsed {
+				t.Errorf("makeRequest() took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
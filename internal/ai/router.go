@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// Router implements model.AIService by dispatching each request to the
+// provider configured for its model name. Models without a route are served
+// by the default provider.
+type Router struct {
+	providers map[string]model.AIService
+	config    *config.ProvidersConfig
+	logger    logger.Logger
+}
+
+// NewRouter creates a router from the provider configuration. Providers must
+// be registered with Register before the router serves requests.
+func NewRouter(cfg *config.ProvidersConfig, logger logger.Logger) *Router {
+	return &Router{
+		providers: make(map[string]model.AIService),
+		config:    cfg,
+		logger:    logger,
+	}
+}
+
+// Register adds a provider under the given name
+func (r *Router) Register(name string, service model.AIService) {
+	r.providers[name] = service
+}
+
+// ProviderFor returns the name of the provider that serves the given model
+func (r *Router) ProviderFor(modelName string) string {
+	return r.config.ProviderFor(modelName)
+}
+
+// resolve returns the provider service for a model
+func (r *Router) resolve(modelName string) (model.AIService, error) {
+	name := r.ProviderFor(modelName)
+	service, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %q for model %q is not configured", name, modelName)
+	}
+	return service, nil
+}
+
+// taskService returns the provider used for task pipelines (sentiment,
+// summarization, classification, entity extraction, translation, question
+// answering) that are only available on the Hugging Face Inference API
+func (r *Router) taskService() (model.AIService, error) {
+	if service, ok := r.providers[config.ProviderHuggingFace]; ok {
+		return service, nil
+	}
+	return r.resolve("")
+}
+
+// GenerateText implements model.AIService
+func (r *Router) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	service, err := r.resolve(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	return service.GenerateText(ctx, req)
+}
+
+// GenerateCompletion implements model.AIService
+func (r *Router) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	service, err := r.resolve(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	return service.GenerateCompletion(ctx, req)
+}
+
+// GenerateTextStream implements model.AIService
+func (r *Router) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	service, err := r.resolve(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	return service.GenerateTextStream(ctx, req, fn)
+}
+
+// AnalyzeSentiment implements model.AIService
+func (r *Router) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.AnalyzeSentiment(ctx, req)
+}
+
+// SummarizeText implements model.AIService
+func (r *Router) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.SummarizeText(ctx, req)
+}
+
+// Embed implements model.AIService
+func (r *Router) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	service, err := r.resolve(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	return service.Embed(ctx, req)
+}
+
+// ClassifyZeroShot implements model.AIService
+func (r *Router) ClassifyZeroShot(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.ClassifyZeroShot(ctx, req)
+}
+
+// ExtractEntities implements model.AIService
+func (r *Router) ExtractEntities(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.ExtractEntities(ctx, req)
+}
+
+// Translate implements model.AIService
+func (r *Router) Translate(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.Translate(ctx, req)
+}
+
+// AnswerQuestion implements model.AIService
+func (r *Router) AnswerQuestion(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+	service, err := r.taskService()
+	if err != nil {
+		return nil, err
+	}
+	return service.AnswerQuestion(ctx, question, contextText)
+}
+
+// ValidateModel implements model.AIService
+func (r *Router) ValidateModel(ctx context.Context, modelName string) (*model.ModelInfo, error) {
+	service, err := r.resolve(modelName)
+	if err != nil {
+		return nil, err
+	}
+	return service.ValidateModel(ctx, modelName)
+}
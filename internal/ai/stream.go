@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// ErrStreamTruncated is returned when an upstream stream closes before its
+// final event, so that a cut-off generation is not mistaken for a complete one
+var ErrStreamTruncated = errors.New("stream ended before the final event")
+
+// HuggingFaceStreamEvent represents a single server-sent event from a
+// streaming text generation request. Both the Hugging Face Inference API and
+// Text Generation Inference emit this format.
+type HuggingFaceStreamEvent struct {
+	Token struct {
+		ID      int      `json:"id"`
+		Text    string   `json:"text"`
+		LogProb *float64 `json:"logprob,omitempty"`
+		Special bool     `json:"special"`
+	} `json:"token"`
+	GeneratedText *string `json:"generated_text"`
+	Details       *struct {
+		FinishReason    string `json:"finish_reason"`
+		GeneratedTokens int    `json:"generated_tokens"`
+	} `json:"details"`
+	Error string `json:"error,omitempty"`
+}
+
+// streamResult accumulates the output of a streaming generation
+type streamResult struct {
+	text             strings.Builder
+	finishReason     string
+	completionTokens int
+}
+
+// response builds the final AI response for a completed stream
+func (r *streamResult) response(req *model.AIRequest, promptTokens int, processingTime time.Duration) *model.AIResponse {
+	return &model.AIResponse{
+		ID:           req.ID,
+		Model:        req.Model,
+		GeneratedAt:  time.Now(),
+		ProcessingMs: processingTime.Milliseconds(),
+		Choices: []model.Choice{
+			{
+				Index:        0,
+				Text:         r.text.String(),
+				FinishReason: r.finishReason,
+			},
+		},
+		Usage: model.Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: r.completionTokens,
+			TotalTokens:      promptTokens + r.completionTokens,
+		},
+	}
+}
+
+// scanSSE calls fn with the payload of every "data:" line in an event stream
+func scanSSE(ctx context.Context, body io.Reader, fn func(data string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// readGenerationStream consumes a text generation event stream, forwarding
+// every token to fn
+func readGenerationStream(ctx context.Context, body io.Reader, req *model.AIRequest, fn model.StreamFunc) (*streamResult, error) {
+	result := &streamResult{finishReason: "stop"}
+	index := 0
+	finished := false
+
+	err := scanSSE(ctx, body, func(data string) error {
+		var event HuggingFaceStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("failed to parse stream event: %w", err)
+		}
+		if event.Error != "" {
+			return fmt.Errorf("stream error: %s", event.Error)
+		}
+
+		if !event.Token.Special {
+			result.text.WriteString(event.Token.Text)
+			result.completionTokens++
+		}
+
+		if err := fn(&model.StreamChunk{
+			ID:      req.ID,
+			Model:   req.Model,
+			Index:   index,
+			Text:    event.Token.Text,
+			Special: event.Token.Special,
+		}); err != nil {
+			return err
+		}
+		index++
+
+		// The last event carries the generated text and its details
+		if event.GeneratedText != nil || event.Details != nil {
+			finished = true
+		}
+		if event.Details != nil {
+			if event.Details.FinishReason != "" {
+				result.finishReason = event.Details.FinishReason
+			}
+			if event.Details.GeneratedTokens > 0 {
+				result.completionTokens = event.Details.GeneratedTokens
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, ErrStreamTruncated
+	}
+
+	return result, nil
+}
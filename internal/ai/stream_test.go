@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestGenerateTextStream(t *testing.T) {
+	tokens := []string{
+		`{"token": {"id": 1, "text": "Hello", "special": false}, "generated_text": null, "details": null}`,
+		`{"token": {"id": 2, "text": " world", "special": false}, "generated_text": null, "details": null}`,
+	}
+	final := `{"token": {"id": 0, "text": "</s>", "special": true}, "generated_text": "Hello world", "details": {"finish_reason": "eos_token", "generated_tokens": 2}}`
+
+	tests := []struct {
+		name       string
+		events     []string
+		disconnect bool
+		wantText   string
+		wantErr    error
+	}{
+		{name: "complete stream", events: append(tokens, final), wantText: "Hello world"},
+		{name: "truncated stream", events: tokens, wantErr: ErrStreamTruncated},
+		{name: "client disconnect", events: tokens, disconnect: true, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				for _, event := range tt.events {
+					fmt.Fprintf(w, "data:%s\n\n", event)
+					w.(http.Flusher).Flush()
+				}
+				if tt.disconnect {
+					<-r.Context().Done()
+				}
+			}))
+			defer server.Close()
+
+			s := NewHuggingFaceService(&config.HuggingFaceConfig{BaseURL: server.URL}, nil, metrics.New(), logger.NewNoopLogger())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var streamed strings.Builder
+			response, err := s.GenerateTextStream(ctx, &model.AIRequest{ID: "req-1", Model: "gpt2", Prompt: "Hi"}, func(chunk *model.StreamChunk) error {
+				if !chunk.Special {
+					streamed.WriteString(chunk.Text)
+				}
+				if tt.disconnect && streamed.String() == "Hello world" {
+					cancel()
+				}
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GenerateTextStream() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateTextStream() unexpected error = %v", err)
+			}
+			if streamed.String() != tt.wantText {
+				t.Errorf("GenerateTextStream() streamed %q, want %q", streamed.String(), tt.wantText)
+			}
+			if got := response.Choices[0].Text; got != tt.wantText {
+				t.Errorf("GenerateTextStream() text = %q, want %q", got, tt.wantText)
+			}
+			if response.Choices[0].FinishReason != "eos_token" || response.Usage.CompletionTokens != 2 {
+				t.Errorf("GenerateTextStream() finish reason %q with %d tokens, want eos_token with 2",
+					response.Choices[0].FinishReason, response.Usage.CompletionTokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// maxSummaryPasses bounds the rounds of a map-reduce summarization. The last
+// round summarizes whatever remains in a single request.
+const maxSummaryPasses = 4
+
+// SummarizeText summarizes the given text. With the map-reduce strategy text
+// longer than the configured chunk size is split on paragraph and sentence
+// boundaries, the chunks are summarized concurrently and the combined
+// summaries are summarized again until they fit max_length.
+func (s *HuggingFaceService) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	modelName := taskModel(req.Model, s.config.SummarizationModel)
+	if req.Model != "" {
+		if err := s.checkTask(ctx, modelName, taskSummarization); err != nil {
+			return nil, err
+		}
+	}
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = model.SummaryStrategyMapReduce
+	}
+
+	s.logger.Info(ctx, "Starting text summarization", map[string]interface{}{
+		"model":       modelName,
+		"text_length": len(req.Text),
+		"max_length":  req.MaxLength,
+		"strategy":    strategy,
+	})
+
+	var (
+		summary        string
+		chunks, passes int
+		err            error
+	)
+	if strategy == model.SummaryStrategySingle {
+		chunks, passes = 1, 1
+		summary, err = s.summarize(ctx, modelName, req.Text, req.MaxLength)
+	} else {
+		summary, chunks, passes, err = s.mapReduceSummary(ctx, modelName, req.Text, req.MaxLength)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SummaryResponse{
+		OriginalText: req.Text,
+		Summary:      summary,
+		Compression:  float64(len(summary)) / float64(len(req.Text)),
+		Model:        modelName,
+		Strategy:     strategy,
+		Chunks:       chunks,
+		Passes:       passes,
+	}, nil
+}
+
+// mapReduceSummary summarizes text in rounds until the combined chunk
+// summaries fit maxLength tokens. It returns the summary, the number of chunks
+// of the first round and the number of rounds.
+func (s *HuggingFaceService) mapReduceSummary(ctx context.Context, modelName, text string, maxLength int) (string, int, int, error) {
+	// Ask for chunk summaries of at most half a chunk so that every round
+	// shrinks the text
+	chunkLength := min(maxLength, max(s.config.SummaryChunkSize/8, 1))
+
+	chunks := 0
+	for pass := 1; ; pass++ {
+		pieces := splitText(text, s.config.SummaryChunkSize)
+		if pass == 1 {
+			chunks = len(pieces)
+		}
+		if len(pieces) == 1 || pass == maxSummaryPasses {
+			summary, err := s.summarize(ctx, modelName, text, maxLength)
+			return summary, chunks, pass, err
+		}
+
+		summaries, err := s.summarizeChunks(ctx, modelName, pieces, chunkLength)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		combined := strings.Join(summaries, "\n")
+
+		s.logger.Debug(ctx, "Summarization pass completed", map[string]interface{}{
+			"pass":            pass,
+			"chunks":          len(pieces),
+			"combined_length": len(combined),
+		})
+
+		if s.tokens.Count(modelName, combined) <= maxLength {
+			return combined, chunks, pass, nil
+		}
+		if len(combined) >= len(text) {
+			// The model is not shrinking the text; finish in one request
+			summary, err := s.summarize(ctx, modelName, combined, maxLength)
+			return summary, chunks, pass + 1, err
+		}
+		text = combined
+	}
+}
+
+// summarizeChunks summarizes pieces with at most SummaryConcurrency requests
+// in flight and returns the non-empty summaries in order. The first failure
+// cancels the remaining requests.
+func (s *HuggingFaceService) summarizeChunks(ctx context.Context, modelName string, pieces []string, maxLength int) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := s.config.SummaryConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(pieces) {
+		workers = len(pieces)
+	}
+
+	results := make([]string, len(pieces))
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				piece := strings.TrimSpace(pieces[index])
+				if piece == "" || ctx.Err() != nil {
+					continue
+				}
+				summary, err := s.summarize(ctx, modelName, piece, maxLength)
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("chunk %d of %d: %w", index+1, len(pieces), err)
+						cancel()
+					})
+					continue
+				}
+				results[index] = strings.TrimSpace(summary)
+			}
+		}()
+	}
+
+	for i := range pieces {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]string, 0, len(results))
+	for _, summary := range results {
+		if summary != "" {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+// summarize summarizes text in a single request
+func (s *HuggingFaceService) summarize(ctx context.Context, modelName, text string, maxLength int) (string, error) {
+	hfReq := &HuggingFaceRequest{
+		Inputs: text,
+		Parameters: map[string]interface{}{
+			"max_length": maxLength,
+			"min_length": maxLength / 4, // Min length is 25% of max
+		},
+	}
+
+	response, err := s.makeRequest(ctx, modelName, hfReq)
+	if err != nil {
+		return "", err
+	}
+
+	var hfResponses []HuggingFaceResponse
+	if err := json.Unmarshal(response, &hfResponses); err != nil {
+		return "", fmt.Errorf("failed to parse summarization response: %w", err)
+	}
+
+	if len(hfResponses) == 0 {
+		return "", fmt.Errorf("no summarization result")
+	}
+
+	return hfResponses[0].SummaryText, nil
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestSummarizeTextStrategies(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req HuggingFaceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		text := req.Inputs.(string)
+		if strings.Contains(text, "fail") {
+			http.Error(w, "model error", http.StatusBadRequest)
+			return
+		}
+		// Keep the first sentence of the input
+		summary, _, _ := strings.Cut(text, ".")
+		json.NewEncoder(w).Encode([]HuggingFaceResponse{{SummaryText: strings.TrimSpace(summary) + "."}})
+	}))
+	defer server.Close()
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{
+		BaseURL:            server.URL,
+		SummarizationModel: "facebook/bart-large-cnn",
+		SummaryChunkSize:   200,
+		SummaryConcurrency: 2,
+	}, nil, metrics.New(), logger.NewNoopLogger())
+
+	var sentences []string
+	for i := 0; i < 40; i++ {
+		sentences = append(sentences, "Sentence number "+strings.Repeat("x", i%5)+" of a long report.")
+	}
+	long := strings.Join(sentences, " ")
+
+	tests := []struct {
+		name         string
+		req          model.SummaryRequest
+		wantRequests int32
+		wantChunks   int
+		wantPasses   int
+		wantErr      bool
+	}{
+		{
+			name:         "single request",
+			req:          model.SummaryRequest{Text: long, MaxLength: 20, Strategy: model.SummaryStrategySingle},
+			wantRequests: 1,
+			wantChunks:   1,
+			wantPasses:   1,
+		},
+		{
+			name:         "short text",
+			req:          model.SummaryRequest{Text: "A short note. Nothing more.", MaxLength: 20},
+			wantRequests: 1,
+			wantChunks:   1,
+			wantPasses:   1,
+		},
+		{
+			name: "map reduce",
+			req:  model.SummaryRequest{Text: long, MaxLength: 20},
+			// 8 chunk summaries, then the 2 chunks of their combination
+			wantRequests: 8 + 2,
+			wantChunks:   8,
+			wantPasses:   2,
+		},
+		{
+			name:    "failing chunk",
+			req:     model.SummaryRequest{Text: long + " This chunk will fail.", MaxLength: 20},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			got, err := s.SummarizeText(context.Background(), &tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SummarizeText() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SummarizeText() unexpected error = %v", err)
+			}
+			if got.Chunks != tt.wantChunks || got.Passes != tt.wantPasses {
+				t.Errorf("SummarizeText() chunks/passes = %v/%v, want %v/%v", got.Chunks, got.Passes, tt.wantChunks, tt.wantPasses)
+			}
+			if n := requests.Load(); n != tt.wantRequests {
+				t.Errorf("SummarizeText() made %v requests, want %v", n, tt.wantRequests)
+			}
+			if got.Summary == "" || len(got.Summary)/4 > tt.req.MaxLength {
+				t.Errorf("SummarizeText() summary = %q, want at most %v tokens", got.Summary, tt.req.MaxLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// Hub pipeline tags of the tasks served by HuggingFaceService
+const (
+	taskSentiment     = "text-classification"
+	taskSummarization = "summarization"
+	taskZeroShot      = "zero-shot-classification"
+	taskNER           = "token-classification"
+	taskTranslation   = "translation"
+	taskQA            = "question-answering"
+)
+
+// compatibleTags lists the pipeline tags of models able to serve each task.
+// Sequence-to-sequence models are often tagged text2text-generation, and NLI
+// models used for zero-shot classification text-classification.
+var compatibleTags = map[string][]string{
+	taskSentiment:     {taskSentiment},
+	taskSummarization: {taskSummarization, "text2text-generation"},
+	taskZeroShot:      {taskZeroShot, "text-classification"},
+	taskNER:           {taskNER},
+	taskTranslation:   {taskTranslation, "text2text-generation"},
+	taskQA:            {taskQA},
+}
+
+// taskModel returns the requested model, or the configured default when the
+// request names none
+func taskModel(requested, configured string) string {
+	if requested != "" {
+		return requested
+	}
+	return configured
+}
+
+// checkTask verifies that a caller-chosen model serves task according to its
+// Hub pipeline tag. Models without a tag are accepted, and so is every model
+// whose metadata cannot be obtained, since the inference call itself will
+// fail for a model that does not exist.
+func (s *HuggingFaceService) checkTask(ctx context.Context, modelName, task string) error {
+	info, err := s.modelInfo(ctx, modelName)
+	if err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			return &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: errResp.Message,
+				Type:    "invalid_model",
+			}
+		}
+		s.logger.Warn(ctx, "Could not verify model task", map[string]interface{}{
+			"model": modelName,
+			"task":  task,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if info.PipelineTag == "" || slices.Contains(compatibleTags[task], info.PipelineTag) {
+		return nil
+	}
+	return &model.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("Model %q does not support %s (pipeline: %s)", modelName, task, info.PipelineTag),
+		Type:    "invalid_model",
+	}
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestCheckTask(t *testing.T) {
+	lookups := 0
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		switch r.URL.Path {
+		case "/api/models/facebook/bart-large-cnn":
+			w.Write([]byte(`{"id": "facebook/bart-large-cnn", "pipeline_tag": "summarization"}`))
+		case "/api/models/google/flan-t5-base":
+			w.Write([]byte(`{"id": "google/flan-t5-base", "pipeline_tag": "text2text-generation"}`))
+		case "/api/models/gpt2":
+			w.Write([]byte(`{"id": "gpt2", "pipeline_tag": "text-generation"}`))
+		case "/api/models/org/untagged":
+			w.Write([]byte(`{"id": "org/untagged"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer hub.Close()
+
+	s := NewHuggingFaceService(&config.HuggingFaceConfig{HubURL: hub.URL}, nil, metrics.New(), logger.NewNoopLogger())
+
+	tests := []struct {
+		name     string
+		model    string
+		task     string
+		wantType string
+	}{
+		{name: "matching tag", model: "facebook/bart-large-cnn", task: taskSummarization},
+		{name: "compatible tag", model: "google/flan-t5-base", task: taskSummarization},
+		{name: "untagged model", model: "org/untagged", task: taskSentiment},
+		{name: "wrong task", model: "gpt2", task: taskSentiment, wantType: "invalid_model"},
+		{name: "unknown model", model: "org/missing", task: taskSentiment, wantType: "invalid_model"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkTask(context.Background(), tt.model, tt.task)
+			if tt.wantType == "" {
+				if err != nil {
+					t.Errorf("checkTask() unexpected error = %v", err)
+				}
+				return
+			}
+			errResp, ok := err.(*model.ErrorResponse)
+			if !ok || errResp.Type != tt.wantType {
+				t.Errorf("checkTask() error = %v, want type %v", err, tt.wantType)
+			}
+		})
+	}
+
+	before := lookups
+	if err := s.checkTask(context.Background(), "facebook/bart-large-cnn", taskSummarization); err != nil {
+		t.Errorf("checkTask() unexpected error = %v", err)
+	}
+	if lookups != before {
+		t.Errorf("checkTask() looked up a cached model again")
+	}
+
+	hub.Close()
+	if err := s.checkTask(context.Background(), "org/offline", taskSentiment); err != nil {
+		t.Errorf("checkTask() with the Hub unreachable error = %v, want nil", err)
+	}
+}
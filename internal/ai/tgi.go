@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// TGIService implements text generation against a self-hosted Text
+// Generation Inference server. A TGI server serves a single model, so the
+// model name is only used for routing, metrics and responses.
+type TGIService struct {
+	unsupportedService
+	client  *providerClient
+	tokens  *tokenizer.Counter
+	metrics *metrics.Metrics
+	logger  logger.Logger
+}
+
+// TGIRequest represents a request to the TGI /generate endpoints
+type TGIRequest struct {
+	Inputs     string                 `json:"inputs"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// TGIResponse represents a response from the TGI /generate endpoint
+type TGIResponse struct {
+	GeneratedText string `json:"generated_text"`
+	Details       *struct {
+		FinishReason    string `json:"finish_reason"`
+		GeneratedTokens int    `json:"generated_tokens"`
+	} `json:"details,omitempty"`
+}
+
+// NewTGIService creates a new Text Generation Inference service instance
+func NewTGIService(config *config.ProviderConfig, tokens *tokenizer.Counter, metrics *metrics.Metrics, logger logger.Logger) *TGIService {
+	return &TGIService{
+		unsupportedService: unsupportedService{provider: "tgi"},
+		client:             newProviderClient(config, metrics),
+		tokens:             tokens,
+		metrics:            metrics,
+		logger:             logger,
+	}
+}
+
+// GenerateText generates text using the TGI server
+func (s *TGIService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	s.logger.Info(ctx, "Starting TGI text generation", map[string]interface{}{
+		"request_id": req.ID,
+		"model":      req.Model,
+	})
+
+	body, err := s.client.post(ctx, "/generate", req.Model, newTGIRequest(req))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to generate text", map[string]interface{}{
+			"request_id": req.ID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	var tgiResp TGIResponse
+	if err := json.Unmarshal(body, &tgiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	processingTime := time.Since(startTime)
+	promptTokens := s.tokens.CountInput(req.Model, req.Prompt)
+	completionTokens := s.tokens.Count(req.Model, tgiResp.GeneratedText)
+	finishReason := "stop"
+	if tgiResp.Details != nil {
+		if tgiResp.Details.FinishReason != "" {
+			finishReason = tgiResp.Details.FinishReason
+		}
+		if tgiResp.Details.GeneratedTokens > 0 {
+			completionTokens = tgiResp.Details.GeneratedTokens
+		}
+	}
+	s.metrics.AddTokens(req.Model, promptTokens, completionTokens)
+
+	return &model.AIResponse{
+		ID:           req.ID,
+		Model:        req.Model,
+		GeneratedAt:  time.Now(),
+		ProcessingMs: processingTime.Milliseconds(),
+		Choices: []model.Choice{
+			{
+				Index:        0,
+				Text:         tgiResp.GeneratedText,
+				FinishReason: finishReason,
+			},
+		},
+		Usage: model.Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}, nil
+}
+
+// GenerateCompletion is an alias for GenerateText for compatibility
+func (s *TGIService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return s.GenerateText(ctx, req)
+}
+
+// GenerateTextStream streams tokens from the TGI /generate_stream endpoint
+func (s *TGIService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	if err := req.ValidateWithin(model.MaxTemperature); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	body, err := s.client.stream(ctx, "/generate_stream", req.Model, newTGIRequest(req))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to start text stream", map[string]interface{}{
+			"request_id": req.ID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+	defer body.Close()
+
+	result, err := readGenerationStream(ctx, body, req, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	response := result.response(req, s.tokens.CountInput(req.Model, req.Prompt), time.Since(startTime))
+	s.metrics.AddTokens(req.Model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	return response, nil
+}
+
+// newTGIRequest builds a TGI payload. TGI rejects zero temperature and top_p,
+// so unset sampling parameters are omitted rather than sent as zero.
+func newTGIRequest(req *model.AIRequest) *TGIRequest {
+	params := map[string]interface{}{
+		"details": true,
+	}
+	if req.MaxTokens > 0 {
+		params["max_new_tokens"] = req.MaxTokens
+	}
+	if req.Temperature > 0 {
+		params["temperature"] = req.Temperature
+		params["do_sample"] = true
+	}
+	if req.TopP > 0 && req.TopP < 1 {
+		params["top_p"] = req.TopP
+	}
+	for k, v := range req.Parameters {
+		params[k] = v
+	}
+	return &TGIRequest{Inputs: req.Prompt, Parameters: params}
+}
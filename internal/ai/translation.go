@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// floresCodes maps ISO 639-1 codes to the FLORES-200 codes expected by NLLB
+// models. Codes already in FLORES form (e.g. "eng_Latn") are passed through.
+var floresCodes = map[string]string{
+	"ar": "arb_Arab", "cs": "ces_Latn", "da": "dan_Latn", "de": "deu_Latn",
+	"el": "ell_Grek", "en": "eng_Latn", "es": "spa_Latn", "fi": "fin_Latn",
+	"fr": "fra_Latn", "he": "heb_Hebr", "hi": "hin_Deva", "hu": "hun_Latn",
+	"id": "ind_Latn", "it": "ita_Latn", "ja": "jpn_Jpan", "ko": "kor_Hang",
+	"nl": "nld_Latn", "no": "nob_Latn", "pl": "pol_Latn", "pt": "por_Latn",
+	"ro": "ron_Latn", "ru": "rus_Cyrl", "sv": "swe_Latn", "th": "tha_Thai",
+	"tr": "tur_Latn", "uk": "ukr_Cyrl", "vi": "vie_Latn", "zh": "zho_Hans",
+}
+
+// translationResponse is a single entry of the translation pipeline output
+type translationResponse struct {
+	TranslationText string `json:"translation_text"`
+}
+
+// Translate translates text between two languages. The model is taken from
+// the request, else from the configured language pair mapping, else the
+// Helsinki-NLP opus-mt model for the pair. Long inputs are translated in
+// chunks split at sentence boundaries.
+func (s *HuggingFaceService) Translate(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	modelName := translationModel(s.config, req)
+	if req.Model != "" {
+		if err := s.checkTask(ctx, modelName, taskTranslation); err != nil {
+			return nil, err
+		}
+	}
+	chunks := splitText(req.Text, s.config.TranslationChunkSize)
+
+	s.logger.Info(ctx, "Starting translation", map[string]interface{}{
+		"model":       modelName,
+		"pair":        req.LanguagePair(),
+		"text_length": len(req.Text),
+		"chunks":      len(chunks),
+	})
+
+	parameters := translationParameters(modelName, req.SourceLang, req.TargetLang)
+
+	var translated strings.Builder
+	for i, chunk := range chunks {
+		leading, text, trailing := splitSpace(chunk)
+		translated.WriteString(leading)
+		if text != "" {
+			result, err := s.translateChunk(ctx, modelName, text, parameters)
+			if err != nil {
+				return nil, fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+			}
+			translated.WriteString(result)
+		}
+		translated.WriteString(trailing)
+	}
+
+	return &model.TranslationResponse{
+		OriginalText:   req.Text,
+		TranslatedText: translated.String(),
+		SourceLang:     req.SourceLang,
+		TargetLang:     req.TargetLang,
+		Model:          modelName,
+		Chunks:         len(chunks),
+	}, nil
+}
+
+func (s *HuggingFaceService) translateChunk(ctx context.Context, modelName, text string, parameters map[string]interface{}) (string, error) {
+	hfReq := &HuggingFaceRequest{
+		Inputs:     text,
+		Parameters: parameters,
+		Options: map[string]interface{}{
+			"wait_for_model": true,
+		},
+	}
+
+	response, err := s.makeRequest(ctx, modelName, hfReq)
+	if err != nil {
+		return "", err
+	}
+
+	var hfResponses []translationResponse
+	if err := json.Unmarshal(response, &hfResponses); err != nil {
+		return "", fmt.Errorf("failed to parse translation response: %w", err)
+	}
+	if len(hfResponses) == 0 {
+		return "", fmt.Errorf("no translation result")
+	}
+	return hfResponses[0].TranslationText, nil
+}
+
+// translationModel selects the model for a translation request
+func translationModel(cfg *config.HuggingFaceConfig, req *model.TranslationRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	if modelName, ok := config.LookupModel(cfg.TranslationModels, req.LanguagePair()); ok {
+		return modelName
+	}
+	return fmt.Sprintf("Helsinki-NLP/opus-mt-%s-%s", req.SourceLang, req.TargetLang)
+}
+
+// translationParameters returns the language parameters required by
+// multilingual models. Bilingual models such as opus-mt need none.
+func translationParameters(modelName, source, target string) map[string]interface{} {
+	name := strings.ToLower(modelName)
+	switch {
+	case strings.Contains(name, "nllb"):
+		return map[string]interface{}{
+			"src_lang": floresCode(source),
+			"tgt_lang": floresCode(target),
+		}
+	case strings.Contains(name, "m2m100"):
+		return map[string]interface{}{
+			"src_lang": source,
+			"tgt_lang": target,
+		}
+	default:
+		return nil
+	}
+}
+
+func floresCode(code string) string {
+	if flores, ok := floresCodes[code]; ok {
+		return flores
+	}
+	return code
+}
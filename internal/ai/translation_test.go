@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+func TestTranslationModel(t *testing.T) {
+	cfg := &config.HuggingFaceConfig{
+		TranslationModels: map[string]string{
+			"en-de": "Helsinki-NLP/opus-mt-en-de",
+			"en-*":  "facebook/m2m100_418M",
+			"*":     "facebook/nllb-200-distilled-600M",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		req        model.TranslationRequest
+		wantModel  string
+		wantParams map[string]interface{}
+	}{
+		{
+			name:      "exact pair",
+			req:       model.TranslationRequest{SourceLang: "en", TargetLang: "de"},
+			wantModel: "Helsinki-NLP/opus-mt-en-de",
+		},
+		{
+			name:       "source prefix",
+			req:        model.TranslationRequest{SourceLang: "en", TargetLang: "ja"},
+			wantModel:  "facebook/m2m100_418M",
+			wantParams: map[string]interface{}{"src_lang": "en", "tgt_lang": "ja"},
+		},
+		{
+			name:       "catch-all",
+			req:        model.TranslationRequest{SourceLang: "fr", TargetLang: "zh"},
+			wantModel:  "facebook/nllb-200-distilled-600M",
+			wantParams: map[string]interface{}{"src_lang": "fra_Latn", "tgt_lang": "zho_Hans"},
+		},
+		{
+			name:      "request override",
+			req:       model.TranslationRequest{Model: "Helsinki-NLP/opus-mt-tc-big-en-fr", SourceLang: "en", TargetLang: "fr"},
+			wantModel: "Helsinki-NLP/opus-mt-tc-big-en-fr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translationModel(cfg, &tt.req)
+			if got != tt.wantModel {
+				t.Errorf("translationModel() = %v, want %v", got, tt.wantModel)
+			}
+			params := translationParameters(got, tt.req.SourceLang, tt.req.TargetLang)
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("translationParameters() = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+
+	unmapped := translationModel(&config.HuggingFaceConfig{}, &model.TranslationRequest{SourceLang: "de", TargetLang: "en"})
+	if unmapped != "Helsinki-NLP/opus-mt-de-en" {
+		t.Errorf("translationModel() without mapping = %v, want Helsinki-NLP/opus-mt-de-en", unmapped)
+	}
+}
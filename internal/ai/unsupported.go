@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// unsupportedService implements model.AIService by rejecting every operation.
+// Providers embed it and override the operations their backend supports.
+type unsupportedService struct {
+	provider string
+}
+
+// unsupported reports an operation the backend cannot perform as a 501
+func (u unsupportedService) unsupported(operation string) error {
+	return &model.ErrorResponse{
+		Code:    http.StatusNotImplemented,
+		Message: fmt.Sprintf("%s provider does not support %s", u.provider, operation),
+		Type:    ErrorTypeUnsupported,
+	}
+}
+
+// GenerateText implements model.AIService
+func (u unsupportedService) GenerateText(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return nil, u.unsupported("text generation")
+}
+
+// GenerateCompletion implements model.AIService
+func (u unsupportedService) GenerateCompletion(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+	return nil, u.unsupported("text completion")
+}
+
+// GenerateTextStream implements model.AIService
+func (u unsupportedService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	return nil, u.unsupported("streaming")
+}
+
+// AnalyzeSentiment implements model.AIService
+func (u unsupportedService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+	return nil, u.unsupported("sentiment analysis")
+}
+
+// SummarizeText implements model.AIService
+func (u unsupportedService) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+	return nil, u.unsupported("summarization")
+}
+
+// Embed implements model.AIService
+func (u unsupportedService) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	return nil, u.unsupported("embeddings")
+}
+
+// ClassifyZeroShot implements model.AIService
+func (u unsupportedService) ClassifyZeroShot(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+	return nil, u.unsupported("zero-shot classification")
+}
+
+// ExtractEntities implements model.AIService
+func (u unsupportedService) ExtractEntities(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+	return nil, u.unsupported("entity extraction")
+}
+
+// Translate implements model.AIService
+func (u unsupportedService) Translate(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+	return nil, u.unsupported("translation")
+}
+
+// AnswerQuestion implements model.AIService
+func (u unsupportedService) AnswerQuestion(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+	return nil, u.unsupported("question answering")
+}
+
+// ValidateModel implements model.AIService. Backends other than the
+// Inference API have no Hub metadata, so any model name is reported as
+// deployable.
+func (u unsupportedService) ValidateModel(ctx context.Context, modelName string) (*model.ModelInfo, error) {
+	if modelName == "" {
+		return nil, emptyModelNameError()
+	}
+	return &model.ModelInfo{
+		ID:         modelName,
+		Provider:   u.provider,
+		Deployable: true,
+		Source:     model.ModelSourceProvider,
+	}, nil
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKeysFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write keys file: %v", err)
+	}
+	return path
+}
+
+func TestFileStoreLookup(t *testing.T) {
+	path := writeKeysFile(t, `{"tenants": [
+		{"id": "search", "name": "Search", "keys": ["plain-key", "sha256:`+HashKey("hashed-key")+`"], "tokens_per_minute": 500},
+		{"id": "support", "keys": ["support-key"], "allowed_models": ["gpt2"]}
+	]}`)
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		wantID string
+	}{
+		{name: "plain key", key: "plain-key", wantID: "search"},
+		{name: "hashed key", key: "hashed-key", wantID: "search"},
+		{name: "second tenant", key: "support-key", wantID: "support"},
+		{name: "unknown key", key: "nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := store.Lookup(context.Background(), tt.key)
+			if tt.wantID == "" {
+				if !errors.Is(err, ErrUnknownKey) {
+					t.Errorf("Lookup() error = %v, want %v", err, ErrUnknownKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error = %v", err)
+			}
+			if tenant.ID != tt.wantID {
+				t.Errorf("Lookup() tenant = %v, want %v", tenant.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestNewFileStoreErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid json", content: `{"tenants": [`},
+		{name: "missing id", content: `{"tenants": [{"keys": ["a"]}]}`},
+		{name: "duplicate key", content: `{"tenants": [{"id": "a", "keys": ["k"]}, {"id": "b", "keys": ["k"]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFileStore(writeKeysFile(t, tt.content)); err == nil {
+				t.Errorf("NewFileStore() expected error but got nil")
+			}
+		})
+	}
+
+	if _, err := NewFileStore(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("NewFileStore() expected error for missing file")
+	}
+}
+
+func TestTenantAllowsModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		model   string
+		want    bool
+	}{
+		{name: "unrestricted", model: "gpt2", want: true},
+		{name: "exact", allowed: []string{"gpt2"}, model: "gpt2", want: true},
+		{name: "prefix", allowed: []string{"meta-llama/*"}, model: "meta-llama/Llama-2-7b-chat-hf", want: true},
+		{name: "not listed", allowed: []string{"gpt2"}, model: "gpt2-large", want: false},
+		{name: "empty model when restricted", allowed: []string{"gpt2"}, model: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &Tenant{ID: "t", AllowedModels: tt.allowed}
+			if got := tenant.AllowsModel(tt.model); got != tt.want {
+				t.Errorf("AllowsModel(%q) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	if got := TenantFromContext(context.Background()); got != nil {
+		t.Errorf("TenantFromContext() = %v, want nil", got)
+	}
+
+	tenant := &Tenant{ID: "search"}
+	if got := TenantFromContext(WithTenant(context.Background(), tenant)); got != tenant {
+		t.Errorf("TenantFromContext() = %v, want %v", got, tenant)
+	}
+}
+
+func TestQuotasReserve(t *testing.T) {
+	quotas := NewQuotas()
+
+	unlimited := &Tenant{ID: "unlimited"}
+	for i := 0; i < 100; i++ {
+		if _, err := quotas.Reserve(unlimited, 1000); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+	}
+
+	requests := &Tenant{ID: "requests", RequestsPerMinute: 2}
+	for i := 0; i < 2; i++ {
+		if _, err := quotas.Reserve(requests, 10); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+	}
+	var quotaErr *QuotaError
+	if _, err := quotas.Reserve(requests, 10); !errors.As(err, &quotaErr) || quotaErr.Limit != "requests_per_minute" {
+		t.Errorf("Reserve() error = %v, want requests_per_minute quota error", err)
+	}
+
+	tokens := &Tenant{ID: "tokens", RequestsPerMinute: 10, TokensPerMinute: 100}
+	r, err := quotas.Reserve(tokens, 80)
+	if err != nil || r == nil {
+		t.Fatalf("Reserve() = %v, %v, want reservation", r, err)
+	}
+	if _, err := quotas.Reserve(tokens, 50); !errors.As(err, &quotaErr) || quotaErr.Limit != "tokens_per_minute" {
+		t.Errorf("Reserve() error = %v, want tokens_per_minute quota error", err)
+	}
+	if quotaErr.RetryAfter <= 0 {
+		t.Errorf("QuotaError.RetryAfter = %v, want > 0", quotaErr.RetryAfter)
+	}
+
+	// Reconciling with the actual usage frees the over-estimate
+	r.Reconcile(20)
+	if _, err := quotas.Reserve(tokens, 50); err != nil {
+		t.Errorf("Reserve() after reconcile unexpected error = %v", err)
+	}
+
+	// Raising the limit in the key store takes effect immediately
+	raised := &Tenant{ID: "requests", RequestsPerMinute: 5}
+	if _, err := quotas.Reserve(raised, 10); err != nil {
+		t.Errorf("Reserve() after raising limit unexpected error = %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileStore resolves keys from a JSON file of the form
+//
+//	{"tenants": [{"id": "search", "keys": ["sha256:<hex>"], "tokens_per_minute": 5000}]}
+//
+// Keys may be listed as "sha256:<hex>" digests (see HashKey) or in plain text.
+// Only digests are kept in memory.
+type FileStore struct {
+	tenants map[string]*Tenant // key digest -> tenant
+}
+
+// fileTenant is the on-disk representation of a tenant
+type fileTenant struct {
+	Tenant
+	Keys []string `json:"keys"`
+}
+
+// NewFileStore loads tenants and keys from path
+func NewFileStore(path string) (*FileStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys file: %w", err)
+	}
+
+	var file struct {
+		Tenants []fileTenant `json:"tenants"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keys file: %w", err)
+	}
+
+	store := &FileStore{tenants: make(map[string]*Tenant)}
+	for i := range file.Tenants {
+		ft := &file.Tenants[i]
+		if ft.ID == "" {
+			return nil, fmt.Errorf("tenant %d: id is required", i)
+		}
+		tenant := ft.Tenant
+		for _, key := range ft.Keys {
+			digest := normalizeKeyHash(key)
+			if existing, ok := store.tenants[digest]; ok {
+				return nil, fmt.Errorf("tenant %q: key already assigned to tenant %q", ft.ID, existing.ID)
+			}
+			store.tenants[digest] = &tenant
+		}
+	}
+
+	return store, nil
+}
+
+// Lookup implements Store
+func (s *FileStore) Lookup(ctx context.Context, key string) (*Tenant, error) {
+	if tenant, ok := s.tenants[HashKey(key)]; ok {
+		return tenant, nil
+	}
+	return nil, ErrUnknownKey
+}
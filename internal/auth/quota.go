@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+)
+
+// QuotaError reports which tenant quota rejected a request
+type QuotaError struct {
+	Limit      string // "requests_per_minute" or "tokens_per_minute"
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return "tenant quota exceeded: " + e.Limit
+}
+
+// Quotas enforces per-tenant request and token limits
+type Quotas struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantQuota
+}
+
+type tenantQuota struct {
+	rpm, tpm int
+	requests *ratelimit.Limiter
+	tokens   *ratelimit.Limiter
+}
+
+// NewQuotas creates an empty quota tracker
+func NewQuotas() *Quotas {
+	return &Quotas{tenants: make(map[string]*tenantQuota)}
+}
+
+// Reserve charges one request and the estimated tokens to the tenant. The
+// returned reservation (nil when the tenant has no token quota) must be
+// reconciled with the actual usage.
+func (q *Quotas) Reserve(tenant *Tenant, tokens int) (*ratelimit.Reservation, error) {
+	quota := q.quotaFor(tenant)
+
+	var request *ratelimit.Reservation
+	if quota.requests != nil {
+		var status ratelimit.Status
+		if request, status = quota.requests.Reserve(tenant.ID, 1); request == nil {
+			return nil, &QuotaError{Limit: "requests_per_minute", RetryAfter: status.RetryAfter}
+		}
+	}
+
+	if quota.tokens == nil {
+		return nil, nil
+	}
+	r, status := quota.tokens.Reserve(tenant.ID, tokens)
+	if r == nil {
+		// The request was rejected, so it does not count against the request quota
+		request.Reconcile(0)
+		return nil, &QuotaError{Limit: "tokens_per_minute", RetryAfter: status.RetryAfter}
+	}
+	return r, nil
+}
+
+// quotaFor returns the limiters for a tenant, recreating them when the
+// tenant's limits have changed in the key store
+func (q *Quotas) quotaFor(tenant *Tenant) *tenantQuota {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quota, ok := q.tenants[tenant.ID]
+	if ok && quota.rpm == tenant.RequestsPerMinute && quota.tpm == tenant.TokensPerMinute {
+		return quota
+	}
+
+	quota = &tenantQuota{rpm: tenant.RequestsPerMinute, tpm: tenant.TokensPerMinute}
+	if tenant.RequestsPerMinute > 0 {
+		quota.requests = ratelimit.NewLimiter(tenant.RequestsPerMinute)
+	}
+	if tenant.TokensPerMinute > 0 {
+		quota.tokens = ratelimit.NewLimiter(tenant.TokensPerMinute)
+	}
+	q.tenants[tenant.ID] = quota
+	return quota
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+)
+
+// ErrUnknownKey is returned by a Store when no tenant owns the presented key
+var ErrUnknownKey = errors.New("unknown API key")
+
+// Tenant is an internal team allowed to call the server
+type Tenant struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	RequestsPerMinute int      `json:"requests_per_minute,omitempty"` // 0 means unlimited
+	TokensPerMinute   int      `json:"tokens_per_minute,omitempty"`   // 0 means unlimited
+	AllowedModels     []string `json:"allowed_models,omitempty"`      // exact names or "prefix*"; empty allows all
+}
+
+// AllowsModel reports whether the tenant may use the given model
+func (t *Tenant) AllowsModel(modelName string) bool {
+	if len(t.AllowedModels) == 0 {
+		return true
+	}
+	patterns := make(map[string]string, len(t.AllowedModels))
+	for _, pattern := range t.AllowedModels {
+		patterns[pattern] = pattern
+	}
+	_, ok := config.LookupModel(patterns, modelName)
+	return ok
+}
+
+// Store resolves API keys to tenants
+type Store interface {
+	// Lookup returns the tenant owning key, or ErrUnknownKey
+	Lookup(ctx context.Context, key string) (*Tenant, error)
+}
+
+// HashKey returns the hex-encoded SHA-256 digest under which keys are stored
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeKeyHash accepts either a "sha256:<hex>" digest or a plain key and
+// returns the digest
+func normalizeKeyHash(key string) string {
+	if digest, ok := strings.CutPrefix(key, "sha256:"); ok {
+		return strings.ToLower(digest)
+	}
+	return HashKey(key)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the authenticated tenant
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the authenticated tenant, or nil when
+// authentication is disabled
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return tenant
+}
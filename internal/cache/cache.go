@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store is a cache backend holding encoded values by key
+type Store interface {
+	// Get returns the value stored under key if present and not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for the given time to live
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes key from the cache
+	Delete(key string)
+}
+
+// Cache lookup outcomes reported to clients
+const (
+	StatusHit    = "HIT"
+	StatusMiss   = "MISS"
+	StatusBypass = "BYPASS"
+)
+
+// Lookup carries per-request cache control between the HTTP layer and the
+// caching service: the handler sets Bypass, the service reports Status
+type Lookup struct {
+	Bypass bool
+	Status string
+}
+
+type lookupKey struct{}
+
+// WithLookup returns a context carrying the lookup
+func WithLookup(ctx context.Context, lookup *Lookup) context.Context {
+	return context.WithValue(ctx, lookupKey{}, lookup)
+}
+
+// LookupFromContext returns the lookup carried by ctx, or nil
+func LookupFromContext(ctx context.Context) *Lookup {
+	lookup, _ := ctx.Value(lookupKey{}).(*Lookup)
+	return lookup
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	c := NewLRU(2)
+
+	c.Set("a", []byte("1"), 0)
+	if got, ok := c.Get("a"); !ok || string(got) != "1" {
+		t.Errorf("Get(a) = (%q, %v), want (%q, true)", got, ok, "1")
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Errorf("Get(missing) ok = true, want false")
+	}
+
+	c.Set("a", []byte("2"), 0)
+	if got, _ := c.Get("a"); string(got) != "2" {
+		t.Errorf("Get(a) after overwrite = %q, want %q", got, "2")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestLRU_Eviction(t *testing.T) {
+	c := NewLRU(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	c.Get("a") // a becomes most recently used
+	c.Set("c", []byte("3"), 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) ok = true, want evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Get(a) ok = false, want present")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Errorf("Get(c) ok = false, want present")
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Set("short", []byte("1"), time.Minute)
+	c.Set("forever", []byte("2"), 0)
+
+	now = now.Add(30 * time.Second)
+	if _, ok := c.Get("short"); !ok {
+		t.Errorf("Get(short) before expiry ok = false, want true")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("short"); ok {
+		t.Errorf("Get(short) after expiry ok = true, want false")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Errorf("Get(forever) ok = false, want true")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want expired entry removed", c.Len())
+	}
+}
+
+func TestLRU_Delete(t *testing.T) {
+	c := NewLRU(2)
+	c.Set("a", []byte("1"), 0)
+	c.Delete("a")
+	c.Delete("missing")
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) after Delete ok = true, want false")
+	}
+}
+
+func TestLookupContext(t *testing.T) {
+	if LookupFromContext(context.Background()) != nil {
+		t.Errorf("LookupFromContext() on empty context should be nil")
+	}
+
+	lookup := &Lookup{Bypass: true}
+	ctx := WithLookup(context.Background(), lookup)
+	if got := LookupFromContext(ctx); got != lookup {
+		t.Errorf("LookupFromContext() = %v, want %v", got, lookup)
+	}
+}
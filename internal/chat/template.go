@@ -0,0 +1,225 @@
+package chat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// Template renders a conversation into the prompt format a model was trained on
+type Template interface {
+	// Render returns the prompt for the conversation, ending where the
+	// assistant's reply should begin
+	Render(messages []model.ChatMessage) string
+	// StopSequences returns the markers that end an assistant turn
+	StopSequences() []string
+}
+
+// templates holds the built-in templates by name
+var templates = map[string]Template{
+	"plain":   plainTemplate{},
+	"chatml":  chatMLTemplate{},
+	"llama2":  llama2Template{},
+	"mistral": mistralTemplate{},
+	"zephyr":  zephyrTemplate{},
+}
+
+// Names returns the names of the built-in templates
+func Names() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the built-in template with the given name
+func Lookup(name string) (Template, bool) {
+	t, ok := templates[name]
+	return t, ok
+}
+
+// Renderer selects the chat template for each model
+type Renderer struct {
+	defaultTemplate Template
+	modelTemplates  map[string]string
+}
+
+// NewRenderer creates a renderer from the chat configuration. It fails if the
+// configuration references an unknown template.
+func NewRenderer(cfg *config.ChatConfig) (*Renderer, error) {
+	defaultName := cfg.DefaultTemplate
+	if defaultName == "" {
+		defaultName = "plain"
+	}
+	defaultTemplate, ok := Lookup(defaultName)
+	if !ok {
+		return nil, fmt.Errorf("unknown chat template %q (available: %s)", defaultName, strings.Join(Names(), ", "))
+	}
+	for pattern, name := range cfg.ModelTemplates {
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("model %q: unknown chat template %q (available: %s)", pattern, name, strings.Join(Names(), ", "))
+		}
+	}
+
+	return &Renderer{
+		defaultTemplate: defaultTemplate,
+		modelTemplates:  cfg.ModelTemplates,
+	}, nil
+}
+
+// TemplateFor returns the template configured for a model
+func (r *Renderer) TemplateFor(modelName string) Template {
+	if name, ok := config.LookupModel(r.modelTemplates, modelName); ok {
+		if t, ok := Lookup(name); ok {
+			return t
+		}
+	}
+	return r.defaultTemplate
+}
+
+// Render renders the conversation with the model's template and returns the
+// prompt together with the stop sequences that end the assistant turn
+func (r *Renderer) Render(modelName string, messages []model.ChatMessage) (string, []string) {
+	t := r.TemplateFor(modelName)
+	return t.Render(messages), t.StopSequences()
+}
+
+// plainTemplate renders "role: content" lines, suitable for base models
+type plainTemplate struct{}
+
+func (plainTemplate) Render(messages []model.ChatMessage) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "%s: %s\n", msg.Role, msg.Content)
+	}
+	b.WriteString("assistant:")
+	return b.String()
+}
+
+func (plainTemplate) StopSequences() []string {
+	return []string{"\nuser:", "\nsystem:"}
+}
+
+// chatMLTemplate renders the ChatML format used by Qwen, OpenHermes and others
+type chatMLTemplate struct{}
+
+func (chatMLTemplate) Render(messages []model.ChatMessage) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "<|im_start|>%s\n%s<|im_end|>\n", msg.Role, msg.Content)
+	}
+	b.WriteString("<|im_start|>assistant\n")
+	return b.String()
+}
+
+func (chatMLTemplate) StopSequences() []string {
+	return []string{"<|im_end|>"}
+}
+
+// zephyrTemplate renders the format used by HuggingFaceH4/zephyr models
+type zephyrTemplate struct{}
+
+func (zephyrTemplate) Render(messages []model.ChatMessage) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "<|%s|>\n%s</s>\n", msg.Role, msg.Content)
+	}
+	b.WriteString("<|assistant|>\n")
+	return b.String()
+}
+
+func (zephyrTemplate) StopSequences() []string {
+	return []string{"</s>"}
+}
+
+// llama2Template renders the Llama-2 chat format, with the system prompt
+// wrapped in <<SYS>> inside the first instruction
+type llama2Template struct{}
+
+func (llama2Template) Render(messages []model.ChatMessage) string {
+	system, turns := splitSystem(messages)
+	if system != "" {
+		system = "<<SYS>>\n" + system + "\n<</SYS>>\n\n"
+	}
+	return renderInstructions(turns, system)
+}
+
+func (llama2Template) StopSequences() []string {
+	return []string{"</s>"}
+}
+
+// mistralTemplate renders the Mistral instruct format, which has no system
+// role; a system prompt is prepended to the first user message
+type mistralTemplate struct{}
+
+func (mistralTemplate) Render(messages []model.ChatMessage) string {
+	system, turns := splitSystem(messages)
+	if system != "" {
+		system += "\n\n"
+	}
+	return renderInstructions(turns, system)
+}
+
+func (mistralTemplate) StopSequences() []string {
+	return []string{"</s>"}
+}
+
+// splitSystem joins all system messages and returns them separately from the
+// user and assistant turns
+func splitSystem(messages []model.ChatMessage) (string, []model.ChatMessage) {
+	var system []string
+	turns := make([]model.ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == model.RoleSystem {
+			system = append(system, msg.Content)
+			continue
+		}
+		turns = append(turns, msg)
+	}
+	return strings.Join(system, "\n"), turns
+}
+
+// renderInstructions renders [INST] blocks shared by the Llama-2 and Mistral
+// formats. Consecutive messages of the same role are merged; the system
+// prefix is placed inside the first instruction.
+func renderInstructions(turns []model.ChatMessage, systemPrefix string) string {
+	var b strings.Builder
+	first := true
+	inInstruction := false
+
+	for _, msg := range turns {
+		switch msg.Role {
+		case model.RoleUser:
+			if inInstruction {
+				b.WriteString("\n" + msg.Content)
+				continue
+			}
+			b.WriteString("<s>[INST] ")
+			if first {
+				b.WriteString(systemPrefix)
+				first = false
+			}
+			b.WriteString(msg.Content)
+			inInstruction = true
+		case model.RoleAssistant:
+			if inInstruction {
+				b.WriteString(" [/INST]")
+				inInstruction = false
+			}
+			b.WriteString(" " + msg.Content + " </s>")
+		}
+	}
+
+	if inInstruction {
+		b.WriteString(" [/INST]")
+	} else if first && systemPrefix != "" {
+		// Only a system prompt was given
+		b.WriteString("<s>[INST] " + strings.TrimSpace(systemPrefix) + " [/INST]")
+	}
+	return b.String()
+}
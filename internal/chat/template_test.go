@@ -0,0 +1,146 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+var conversation = []model.ChatMessage{
+	{Role: model.RoleSystem, Content: "You are helpful."},
+	{Role: model.RoleUser, Content: "Hi"},
+	{Role: model.RoleAssistant, Content: "Hello!"},
+	{Role: model.RoleUser, Content: "How are you?"},
+}
+
+func TestTemplates_Render(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{
+			template: "plain",
+			want:     "system: You are helpful.\nuser: Hi\nassistant: Hello!\nuser: How are you?\nassistant:",
+		},
+		{
+			template: "chatml",
+			want: "<|im_start|>system\nYou are helpful.<|im_end|>\n" +
+				"<|im_start|>user\nHi<|im_end|>\n" +
+				"<|im_start|>assistant\nHello!<|im_end|>\n" +
+				"<|im_start|>user\nHow are you?<|im_end|>\n" +
+				"<|im_start|>assistant\n",
+		},
+		{
+			template: "zephyr",
+			want: "<|system|>\nYou are helpful.</s>\n" +
+				"<|user|>\nHi</s>\n" +
+				"<|assistant|>\nHello!</s>\n" +
+				"<|user|>\nHow are you?</s>\n" +
+				"<|assistant|>\n",
+		},
+		{
+			template: "llama2",
+			want: "<s>[INST] <<SYS>>\nYou are helpful.\n<</SYS>>\n\nHi [/INST] Hello! </s>" +
+				"<s>[INST] How are you? [/INST]",
+		},
+		{
+			template: "mistral",
+			want:     "<s>[INST] You are helpful.\n\nHi [/INST] Hello! </s><s>[INST] How are you? [/INST]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			tmpl, ok := Lookup(tt.template)
+			if !ok {
+				t.Fatalf("Lookup(%q) not found", tt.template)
+			}
+			if got := tmpl.Render(conversation); got != tt.want {
+				t.Errorf("Render() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if len(tmpl.StopSequences()) == 0 {
+				t.Errorf("StopSequences() is empty")
+			}
+		})
+	}
+}
+
+func TestLlama2Template_SystemOnly(t *testing.T) {
+	tmpl, _ := Lookup("llama2")
+	got := tmpl.Render([]model.ChatMessage{{Role: model.RoleSystem, Content: "Be brief."}})
+	want := "<s>[INST] <<SYS>>\nBe brief.\n<</SYS>> [/INST]"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.ChatConfig
+		wantErr bool
+	}{
+		{
+			name:    "zero value uses plain",
+			config:  config.ChatConfig{},
+			wantErr: false,
+		},
+		{
+			name: "valid model templates",
+			config: config.ChatConfig{
+				DefaultTemplate: "chatml",
+				ModelTemplates:  map[string]string{"meta-llama/Llama-2-*": "llama2"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unknown default template",
+			config:  config.ChatConfig{DefaultTemplate: "vicuna"},
+			wantErr: true,
+		},
+		{
+			name: "unknown model template",
+			config: config.ChatConfig{
+				ModelTemplates: map[string]string{"gpt2": "vicuna"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(&tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRenderer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderer_TemplateFor(t *testing.T) {
+	r, err := NewRenderer(&config.ChatConfig{
+		DefaultTemplate: "plain",
+		ModelTemplates: map[string]string{
+			"meta-llama/Llama-2-*":         "llama2",
+			"HuggingFaceH4/zephyr-7b-beta": "zephyr",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRenderer() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		model string
+		want  Template
+	}{
+		{"meta-llama/Llama-2-7b-chat-hf", llama2Template{}},
+		{"HuggingFaceH4/zephyr-7b-beta", zephyrTemplate{}},
+		{"gpt2", plainTemplate{}},
+	}
+	for _, tt := range tests {
+		if got := r.TemplateFor(tt.model); got != tt.want {
+			t.Errorf("TemplateFor(%q) = %T, want %T", tt.model, got, tt.want)
+		}
+	}
+}
@@ -4,15 +4,36 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Supported inference providers
+const (
+	ProviderHuggingFace = "huggingface"
+	ProviderTGI         = "tgi"
+	ProviderOpenAI      = "openai"
+)
+
+// Supported API key sources
+const (
+	AuthSourceFile = "file"
+)
+
 // Config holds all configuration values
 type Config struct {
 	Server     ServerConfig     `json:"server"`
 	HuggingFace HuggingFaceConfig `json:"hugging_face"`
 	Logger     LoggerConfig     `json:"logger"`
 	Database   DatabaseConfig   `json:"database,omitempty"`
+	Providers  ProvidersConfig  `json:"providers"`
+	Fallbacks  FallbackConfig   `json:"fallbacks"`
+	Chat       ChatConfig       `json:"chat"`
+	Cache      CacheConfig      `json:"cache"`
+	Auth       AuthConfig       `json:"auth"`
+	Batch      BatchConfig      `json:"batch"`
+	Jobs       JobsConfig       `json:"jobs"`
+	Models     ModelsConfig     `json:"models"`
 }
 
 // ServerConfig holds server-specific configuration
@@ -23,20 +44,120 @@ type ServerConfig struct {
 	WriteTimeout time.Duration `json:"write_timeout"`
 	IdleTimeout  time.Duration `json:"idle_timeout"`
 	GracefulShutdownTimeout time.Duration `json:"graceful_shutdown_timeout"`
+	ErrorDetails bool          `json:"error_details"` // include service error text, which may quote upstream responses, in error responses
+	MaxBodyBytes int           `json:"max_body_bytes"` // largest accepted request body; 0 for the handler default
 }
 
 // HuggingFaceConfig holds Hugging Face API configuration
 type HuggingFaceConfig struct {
-	APIKey         string        `json:"-"` // Hidden in JSON for security
-	BaseURL        string        `json:"base_url"`
-	DefaultModel   string        `json:"default_model"`
-	Timeout        time.Duration `json:"timeout"`
-	RetryAttempts  int           `json:"retry_attempts"`
-	RetryDelay     time.Duration `json:"retry_delay"`
-	MaxTokens      int           `json:"max_tokens"`
-	Temperature    float32       `json:"temperature"`
-	RateLimitRPM   int           `json:"rate_limit_rpm"`
-	RateLimitTPM   int           `json:"rate_limit_tpm"`
+	APIKey               string               `json:"-"` // Hidden in JSON for security
+	BaseURL              string               `json:"base_url"`
+	DefaultModel         string               `json:"default_model"`
+	Timeout              time.Duration        `json:"timeout"`
+	Retry                RetryConfig          `json:"retry"`
+	RetryPolicies        map[string]string    `json:"retry_policies"`    // model name or "prefix*" -> policy overriding Retry
+	CoalesceRequests     bool                 `json:"coalesce_requests"` // share one upstream call between identical concurrent requests
+	MaxTokens            int                  `json:"max_tokens"`
+	Temperature          float32              `json:"temperature"`
+	RateLimitRPM         int                  `json:"rate_limit_rpm"`
+	RateLimitTPM         int                  `json:"rate_limit_tpm"`
+	HubURL               string               `json:"hub_url"`
+	ModelCatalogFile     string               `json:"model_catalog_file"` // local cache of Hub model metadata
+	ModelInfoTTL         time.Duration        `json:"model_info_ttl"`
+	SentimentModel       string               `json:"sentiment_model"`
+	SummarizationModel   string               `json:"summarization_model"`
+	ZeroShotModel        string               `json:"zero_shot_model"`
+	NERModel             string               `json:"ner_model"`
+	QAModel              string               `json:"qa_model"`
+	TranslationModels    map[string]string    `json:"translation_models"`     // "source-target" or "prefix*" -> model
+	TranslationChunkSize int                  `json:"translation_chunk_size"` // characters per translated chunk
+	QAWindowSize         int                  `json:"qa_window_size"`         // characters of context per question answering call
+	QAWindowOverlap      int                  `json:"qa_window_overlap"`      // characters shared by consecutive windows
+	SummaryChunkSize     int                  `json:"summary_chunk_size"`     // characters per summarized chunk
+	SummaryConcurrency   int                  `json:"summary_concurrency"`    // chunks summarized at once
+	CircuitBreaker       CircuitBreakerConfig `json:"circuit_breaker"`
+}
+
+// CircuitBreakerConfig holds the per-model circuit breaker of the Hugging
+// Face client
+type CircuitBreakerConfig struct {
+	ErrorRatio  float32       `json:"error_ratio"`  // share of failed requests that opens the breaker, 0 disables it
+	MinRequests int           `json:"min_requests"` // requests in the window before the ratio applies
+	Window      time.Duration `json:"window"`
+	OpenTimeout time.Duration `json:"open_timeout"` // how long the breaker stays open before a probe
+}
+
+// RetryConfig holds the retry policy of requests to the Hugging Face API.
+// Retries back off exponentially from Delay up to MaxDelay with jitter, or
+// wait as long as the API asks through Retry-After or the estimated loading
+// time of a model, up to MaxWait.
+type RetryConfig struct {
+	Attempts int           `json:"attempts"` // retries after the first attempt
+	Delay    time.Duration `json:"delay"`
+	MaxDelay time.Duration `json:"max_delay"`
+	MaxWait  time.Duration `json:"max_wait"`
+}
+
+// ProvidersConfig holds configuration for the inference backends and how
+// model names are routed to them
+type ProvidersConfig struct {
+	Default     string            `json:"default"`
+	TGI         ProviderConfig    `json:"tgi"`
+	OpenAI      ProviderConfig    `json:"openai"`
+	ModelRoutes map[string]string `json:"model_routes"` // model name or "prefix*" -> provider
+}
+
+// FallbackConfig holds the models that, in order, take over a request when
+// its model fails
+type FallbackConfig struct {
+	Models        map[string]string `json:"models"`        // model name or "prefix*" -> "model|model"
+	Sentiment     []string          `json:"sentiment"`     // after the sentiment model, unless it has its own chain
+	Summarization []string          `json:"summarization"` // after the summarization model, unless it has its own chain
+}
+
+// ProviderConfig holds connection settings for a self-hosted or third-party backend
+type ProviderConfig struct {
+	BaseURL string        `json:"base_url"`
+	APIKey  string        `json:"-"` // Hidden in JSON for security
+	Timeout time.Duration `json:"timeout"`
+}
+
+// ChatConfig holds the chat templates used to render conversations into prompts
+type ChatConfig struct {
+	DefaultTemplate string            `json:"default_template"`
+	ModelTemplates  map[string]string `json:"model_templates"` // model name or "prefix*" -> template
+}
+
+// CacheConfig holds response cache configuration
+type CacheConfig struct {
+	Enabled    bool          `json:"enabled"`
+	MaxEntries int           `json:"max_entries"`
+	TTL        time.Duration `json:"ttl"`
+}
+
+// BatchConfig holds limits for batch inference requests
+type BatchConfig struct {
+	MaxSize     int `json:"max_size"`
+	Concurrency int `json:"concurrency"`
+}
+
+// JobsConfig holds asynchronous job configuration
+type JobsConfig struct {
+	Workers              int           `json:"workers"`
+	QueueSize            int           `json:"queue_size"`
+	Timeout              time.Duration `json:"timeout"`
+	Retention            time.Duration `json:"retention"`
+	CallbackTimeout      time.Duration `json:"callback_timeout"`
+	CallbackRetries      int           `json:"callback_retries"`
+	CallbackAllowPrivate bool          `json:"callback_allow_private"` // allow callbacks to loopback, link-local and private addresses
+}
+
+// AuthConfig holds API key authentication and CORS configuration
+type AuthConfig struct {
+	Enabled        bool     `json:"enabled"`
+	Source         string   `json:"source"` // "file"
+	KeysFile       string   `json:"keys_file"`
+	AllowedOrigins []string `json:"allowed_origins"`
 }
 
 // LoggerConfig holds logging configuration
@@ -69,6 +190,8 @@ func LoadConfig() (*Config, error) {
 		WriteTimeout:            getEnvAsDuration("SERVER_WRITE_TIMEOUT", "30s"),
 		IdleTimeout:             getEnvAsDuration("SERVER_IDLE_TIMEOUT", "60s"),
 		GracefulShutdownTimeout: getEnvAsDuration("SERVER_GRACEFUL_SHUTDOWN_TIMEOUT", "30s"),
+		ErrorDetails:            getEnvAsBool("SERVER_ERROR_DETAILS", false),
+		MaxBodyBytes:            getEnvAsInt("SERVER_MAX_BODY_BYTES", 1<<20),
 	}
 
 	// Hugging Face configuration
@@ -78,16 +201,42 @@ func LoadConfig() (*Config, error) {
 	}
 
 	config.HuggingFace = HuggingFaceConfig{
-		APIKey:        apiKey,
-		BaseURL:       getEnv("HUGGINGFACE_BASE_URL", "https://api-inference.huggingface.co"),
-		DefaultModel:  getEnv("HUGGINGFACE_DEFAULT_MODEL", "gpt2"),
-		Timeout:       getEnvAsDuration("HUGGINGFACE_TIMEOUT", "30s"),
-		RetryAttempts: getEnvAsInt("HUGGINGFACE_RETRY_ATTEMPTS", 3),
-		RetryDelay:    getEnvAsDuration("HUGGINGFACE_RETRY_DELAY", "1s"),
-		MaxTokens:     getEnvAsInt("HUGGINGFACE_MAX_TOKENS", 100),
-		Temperature:   getEnvAsFloat32("HUGGINGFACE_TEMPERATURE", 0.7),
-		RateLimitRPM:  getEnvAsInt("HUGGINGFACE_RATE_LIMIT_RPM", 60),
-		RateLimitTPM:  getEnvAsInt("HUGGINGFACE_RATE_LIMIT_TPM", 10000),
+		APIKey:               apiKey,
+		BaseURL:              getEnv("HUGGINGFACE_BASE_URL", "https://api-inference.huggingface.co"),
+		DefaultModel:         getEnv("HUGGINGFACE_DEFAULT_MODEL", "gpt2"),
+		Timeout:              getEnvAsDuration("HUGGINGFACE_TIMEOUT", "30s"),
+		MaxTokens:            getEnvAsInt("HUGGINGFACE_MAX_TOKENS", 100),
+		Temperature:          getEnvAsFloat32("HUGGINGFACE_TEMPERATURE", 0.7),
+		RateLimitRPM:         getEnvAsInt("HUGGINGFACE_RATE_LIMIT_RPM", 60),
+		RateLimitTPM:         getEnvAsInt("HUGGINGFACE_RATE_LIMIT_TPM", 10000),
+		HubURL:               getEnv("HUGGINGFACE_HUB_URL", "https://huggingface.co"),
+		ModelCatalogFile:     getEnv("HUGGINGFACE_MODEL_CATALOG", ""),
+		ModelInfoTTL:         getEnvAsDuration("HUGGINGFACE_MODEL_INFO_TTL", "1h"),
+		SentimentModel:       getEnv("HUGGINGFACE_SENTIMENT_MODEL", "cardiffnlp/twitter-roberta-base-sentiment-latest"),
+		SummarizationModel:   getEnv("HUGGINGFACE_SUMMARIZATION_MODEL", "facebook/bart-large-cnn"),
+		ZeroShotModel:        getEnv("HUGGINGFACE_ZERO_SHOT_MODEL", "facebook/bart-large-mnli"),
+		NERModel:             getEnv("HUGGINGFACE_NER_MODEL", "dslim/bert-base-NER"),
+		QAModel:              getEnv("HUGGINGFACE_QA_MODEL", "deepset/roberta-base-squad2"),
+		TranslationModels:    getEnvAsMap("HUGGINGFACE_TRANSLATION_MODELS"),
+		TranslationChunkSize: getEnvAsInt("HUGGINGFACE_TRANSLATION_CHUNK_SIZE", 1000),
+		QAWindowSize:         getEnvAsInt("HUGGINGFACE_QA_WINDOW_SIZE", 1500),
+		QAWindowOverlap:      getEnvAsInt("HUGGINGFACE_QA_WINDOW_OVERLAP", 300),
+		SummaryChunkSize:     getEnvAsInt("HUGGINGFACE_SUMMARY_CHUNK_SIZE", 3000),
+		SummaryConcurrency:   getEnvAsInt("HUGGINGFACE_SUMMARY_CONCURRENCY", 4),
+		CircuitBreaker: CircuitBreakerConfig{
+			ErrorRatio:  getEnvAsFloat32("HUGGINGFACE_BREAKER_ERROR_RATIO", 0.5),
+			MinRequests: getEnvAsInt("HUGGINGFACE_BREAKER_MIN_REQUESTS", 10),
+			Window:      getEnvAsDuration("HUGGINGFACE_BREAKER_WINDOW", "1m"),
+			OpenTimeout: getEnvAsDuration("HUGGINGFACE_BREAKER_OPEN_TIMEOUT", "30s"),
+		},
+		Retry: RetryConfig{
+			Attempts: getEnvAsInt("HUGGINGFACE_RETRY_ATTEMPTS", 3),
+			Delay:    getEnvAsDuration("HUGGINGFACE_RETRY_DELAY", "1s"),
+			MaxDelay: getEnvAsDuration("HUGGINGFACE_RETRY_MAX_DELAY", "30s"),
+			MaxWait:  getEnvAsDuration("HUGGINGFACE_RETRY_MAX_WAIT", "2m"),
+		},
+		RetryPolicies:    getEnvAsMap("HUGGINGFACE_RETRY_POLICIES"),
+		CoalesceRequests: getEnvAsBool("HUGGINGFACE_COALESCE_REQUESTS", true),
 	}
 
 	// Logger configuration
@@ -98,6 +247,87 @@ func LoadConfig() (*Config, error) {
 		Structured: getEnvAsBool("LOG_STRUCTURED", true),
 	}
 
+	// Provider configuration
+	config.Providers = ProvidersConfig{
+		Default: getEnv("DEFAULT_PROVIDER", ProviderHuggingFace),
+		TGI: ProviderConfig{
+			BaseURL: getEnv("TGI_BASE_URL", ""),
+			APIKey:  getEnv("TGI_API_KEY", ""),
+			Timeout: getEnvAsDuration("TGI_TIMEOUT", "60s"),
+		},
+		OpenAI: ProviderConfig{
+			BaseURL: getEnv("OPENAI_BASE_URL", ""),
+			APIKey:  getEnv("OPENAI_API_KEY", ""),
+			Timeout: getEnvAsDuration("OPENAI_TIMEOUT", "60s"),
+		},
+		ModelRoutes: getEnvAsMap("MODEL_PROVIDERS"),
+	}
+
+	// Fallback model configuration
+	config.Fallbacks = FallbackConfig{
+		Models:        getEnvAsMap("FALLBACK_MODELS"),
+		Sentiment:     getEnvAsList("FALLBACK_SENTIMENT_MODELS", nil),
+		Summarization: getEnvAsList("FALLBACK_SUMMARIZATION_MODELS", nil),
+	}
+
+	// Model registry
+	config.Models = ModelsConfig{
+		RegistryFile: getEnv("MODEL_REGISTRY_FILE", ""),
+		TokenizerDir: getEnv("TOKENIZER_DIR", ""),
+	}
+	if config.Models.RegistryFile != "" {
+		models, err := loadModels(config.Models.RegistryFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Models.Models = models
+	} else {
+		config.Models.Models = defaultModels(&config.HuggingFace)
+	}
+	for i := range config.Models.Models {
+		if config.Models.Models[i].Provider == "" {
+			config.Models.Models[i].Provider = config.Providers.ProviderFor(config.Models.Models[i].ID)
+		}
+	}
+
+	// Chat template configuration
+	config.Chat = ChatConfig{
+		DefaultTemplate: getEnv("CHAT_DEFAULT_TEMPLATE", "plain"),
+		ModelTemplates:  getEnvAsMap("CHAT_TEMPLATES"),
+	}
+
+	// Response cache configuration
+	config.Cache = CacheConfig{
+		Enabled:    getEnvAsBool("CACHE_ENABLED", true),
+		MaxEntries: getEnvAsInt("CACHE_MAX_ENTRIES", 1000),
+		TTL:        getEnvAsDuration("CACHE_TTL", "10m"),
+	}
+
+	// Batch inference configuration
+	config.Batch = BatchConfig{
+		MaxSize:     getEnvAsInt("BATCH_MAX_SIZE", 100),
+		Concurrency: getEnvAsInt("BATCH_CONCURRENCY", 4),
+	}
+
+	// Asynchronous job configuration
+	config.Jobs = JobsConfig{
+		Workers:              getEnvAsInt("JOBS_WORKERS", 4),
+		QueueSize:            getEnvAsInt("JOBS_QUEUE_SIZE", 100),
+		Timeout:              getEnvAsDuration("JOBS_TIMEOUT", "10m"),
+		Retention:            getEnvAsDuration("JOBS_RETENTION", "1h"),
+		CallbackTimeout:      getEnvAsDuration("JOBS_CALLBACK_TIMEOUT", "10s"),
+		CallbackRetries:      getEnvAsInt("JOBS_CALLBACK_RETRIES", 3),
+		CallbackAllowPrivate: getEnvAsBool("JOBS_CALLBACK_ALLOW_PRIVATE", false),
+	}
+
+	// Authentication configuration
+	config.Auth = AuthConfig{
+		Enabled:        getEnvAsBool("AUTH_ENABLED", false),
+		Source:         getEnv("AUTH_SOURCE", AuthSourceFile),
+		KeysFile:       getEnv("AUTH_KEYS_FILE", ""),
+		AllowedOrigins: getEnvAsList("CORS_ALLOWED_ORIGINS", nil),
+	}
+
 	// Database configuration (optional)
 	if getEnv("DATABASE_DRIVER", "") != "" {
 		config.Database = DatabaseConfig{
@@ -121,15 +351,160 @@ func (c *Config) Validate() error {
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
+	if c.Server.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body bytes must not be negative")
+	}
 	if c.HuggingFace.MaxTokens <= 0 {
 		return fmt.Errorf("max tokens must be positive")
 	}
 	if c.HuggingFace.Temperature < 0 || c.HuggingFace.Temperature > 1 {
 		return fmt.Errorf("temperature must be between 0 and 1")
 	}
+	if c.HuggingFace.QAWindowSize > 0 && (c.HuggingFace.QAWindowOverlap < 0 || c.HuggingFace.QAWindowOverlap >= c.HuggingFace.QAWindowSize) {
+		return fmt.Errorf("question answering window overlap must be between 0 and the window size")
+	}
+	if c.HuggingFace.SummaryChunkSize < 0 || c.HuggingFace.SummaryConcurrency < 0 {
+		return fmt.Errorf("summary chunk size and concurrency must not be negative")
+	}
+	if err := c.HuggingFace.CircuitBreaker.validate(); err != nil {
+		return err
+	}
+	if err := c.HuggingFace.Retry.validate(); err != nil {
+		return err
+	}
+	for pattern, policy := range c.HuggingFace.RetryPolicies {
+		if _, err := c.HuggingFace.Retry.parse(policy); err != nil {
+			return fmt.Errorf("retry policy %q: %w", pattern, err)
+		}
+	}
+	if err := c.Providers.Validate(); err != nil {
+		return err
+	}
+	if c.Cache.Enabled && c.Cache.MaxEntries <= 0 {
+		return fmt.Errorf("cache max entries must be positive")
+	}
+	if c.Batch.MaxSize < 0 || c.Batch.Concurrency < 0 {
+		return fmt.Errorf("batch max size and concurrency must not be negative")
+	}
+	if err := c.Auth.validate(); err != nil {
+		return err
+	}
+	if err := c.Models.validate(&c.Providers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validate checks the breaker settings when the breaker is enabled
+func (b *CircuitBreakerConfig) validate() error {
+	if b.ErrorRatio < 0 || b.ErrorRatio > 1 {
+		return fmt.Errorf("circuit breaker error ratio must be between 0 and 1")
+	}
+	if b.ErrorRatio > 0 && (b.MinRequests <= 0 || b.Window <= 0 || b.OpenTimeout <= 0) {
+		return fmt.Errorf("circuit breaker min requests, window and open timeout must be positive")
+	}
+	return nil
+}
+
+// RetryPolicy returns the retry policy of the model
+func (h *HuggingFaceConfig) RetryPolicy(modelName string) RetryConfig {
+	if spec, ok := LookupModel(h.RetryPolicies, modelName); ok {
+		if policy, err := h.Retry.parse(spec); err == nil {
+			return policy
+		}
+	}
+	return h.Retry
+}
+
+// parse reads a policy such as "attempts:5;delay:2s;max_wait:5m". Settings
+// it leaves out keep their value in r.
+func (r RetryConfig) parse(spec string) (RetryConfig, error) {
+	policy := r
+	for _, setting := range strings.Split(spec, ";") {
+		key, value, ok := strings.Cut(setting, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return RetryConfig{}, fmt.Errorf("invalid setting %q", setting)
+		}
+
+		var err error
+		switch key {
+		case "attempts":
+			policy.Attempts, err = strconv.Atoi(value)
+		case "delay":
+			policy.Delay, err = time.ParseDuration(value)
+		case "max_delay":
+			policy.MaxDelay, err = time.ParseDuration(value)
+		case "max_wait":
+			policy.MaxWait, err = time.ParseDuration(value)
+		default:
+			return RetryConfig{}, fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return RetryConfig{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return policy, policy.validate()
+}
+
+// validate checks that the policy has no negative settings
+func (r *RetryConfig) validate() error {
+	if r.Attempts < 0 || r.Delay < 0 || r.MaxDelay < 0 || r.MaxWait < 0 {
+		return fmt.Errorf("retry attempts and delays must not be negative")
+	}
+	return nil
+}
+
+// validate checks that the configured key source can be loaded
+func (a *AuthConfig) validate() error {
+	if !a.Enabled {
+		return nil
+	}
+	switch a.Source {
+	case AuthSourceFile:
+		if a.KeysFile == "" {
+			return fmt.Errorf("auth source %q requires AUTH_KEYS_FILE", a.Source)
+		}
+	default:
+		return fmt.Errorf("unknown auth source %q", a.Source)
+	}
+	return nil
+}
+
+// Validate checks that every referenced provider is known and configured
+func (p *ProvidersConfig) Validate() error {
+	if p.Default != "" {
+		if err := p.checkProvider(p.Default); err != nil {
+			return fmt.Errorf("default provider: %w", err)
+		}
+	}
+	for pattern, provider := range p.ModelRoutes {
+		if err := p.checkProvider(provider); err != nil {
+			return fmt.Errorf("model route %q: %w", pattern, err)
+		}
+	}
 	return nil
 }
 
+func (p *ProvidersConfig) checkProvider(name string) error {
+	switch name {
+	case ProviderHuggingFace:
+		return nil
+	case ProviderTGI:
+		if p.TGI.BaseURL == "" {
+			return fmt.Errorf("provider %q requires TGI_BASE_URL", name)
+		}
+		return nil
+	case ProviderOpenAI:
+		if p.OpenAI.BaseURL == "" {
+			return fmt.Errorf("provider %q requires OPENAI_BASE_URL", name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown provider %q", name)
+	}
+}
+
 // Helper functions for environment variable parsing
 
 func getEnv(key, defaultValue string) string {
@@ -166,6 +541,77 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// Chain returns the fallback models of modelName in order
+func (f *FallbackConfig) Chain(modelName string) []string {
+	spec, ok := LookupModel(f.Models, modelName)
+	if !ok {
+		return nil
+	}
+	var chain []string
+	for _, fallback := range strings.Split(spec, "|") {
+		if fallback = strings.TrimSpace(fallback); fallback != "" {
+			chain = append(chain, fallback)
+		}
+	}
+	return chain
+}
+
+// LookupModel returns the value of the entry in patterns that matches
+// modelName. Keys are exact model names or prefixes ending in "*"; exact names
+// take precedence and the longest matching prefix wins.
+func LookupModel(patterns map[string]string, modelName string) (string, bool) {
+	if value, ok := patterns[modelName]; ok {
+		return value, true
+	}
+
+	bestLen := -1
+	var best string
+	for pattern, value := range patterns {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if !ok || !strings.HasPrefix(modelName, prefix) {
+			continue
+		}
+		if len(prefix) > bestLen {
+			bestLen = len(prefix)
+			best = value
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// getEnvAsMap parses a comma-separated list of key=value pairs
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return result
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// getEnvAsList parses a comma-separated list such as "a, b"
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func getEnvAsDuration(key, defaultValue string) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -23,15 +24,57 @@ func TestLoadConfig(t *testing.T) {
 	if config.Server.Host != "localhost" {
 		t.Errorf("Server.Host = %v, want %v", config.Server.Host, "localhost")
 	}
+	if config.Server.ErrorDetails {
+		t.Errorf("Server.ErrorDetails = %v, want false", config.Server.ErrorDetails)
+	}
+	if config.Server.MaxBodyBytes != 1<<20 {
+		t.Errorf("Server.MaxBodyBytes = %v, want %v", config.Server.MaxBodyBytes, 1<<20)
+	}
+	if len(config.Auth.AllowedOrigins) != 0 {
+		t.Errorf("Auth.AllowedOrigins = %v, want none", config.Auth.AllowedOrigins)
+	}
 	if config.HuggingFace.APIKey != "test-api-key" {
 		t.Errorf("HuggingFace.APIKey = %v, want %v", config.HuggingFace.APIKey, "test-api-key")
 	}
 	if config.HuggingFace.DefaultModel != "gpt2" {
 		t.Errorf("HuggingFace.DefaultModel = %v, want %v", config.HuggingFace.DefaultModel, "gpt2")
 	}
+	if !config.HuggingFace.CoalesceRequests {
+		t.Errorf("HuggingFace.CoalesceRequests = %v, want true", config.HuggingFace.CoalesceRequests)
+	}
 	if config.Logger.Level != "info" {
 		t.Errorf("Logger.Level = %v, want %v", config.Logger.Level, "info")
 	}
+	if config.Batch.MaxSize != 100 || config.Batch.Concurrency != 4 {
+		t.Errorf("Batch = %+v, want MaxSize 100 and Concurrency 4", config.Batch)
+	}
+	if config.Jobs.Workers != 4 || config.Jobs.Timeout != 10*time.Minute || config.Jobs.Retention != time.Hour {
+		t.Errorf("Jobs = %+v, want Workers 4, Timeout 10m and Retention 1h", config.Jobs)
+	}
+	if config.HuggingFace.TranslationChunkSize != 1000 {
+		t.Errorf("HuggingFace.TranslationChunkSize = %v, want %v", config.HuggingFace.TranslationChunkSize, 1000)
+	}
+	if config.HuggingFace.SentimentModel != "cardiffnlp/twitter-roberta-base-sentiment-latest" {
+		t.Errorf("HuggingFace.SentimentModel = %v, want %v", config.HuggingFace.SentimentModel, "cardiffnlp/twitter-roberta-base-sentiment-latest")
+	}
+	if config.HuggingFace.SummarizationModel != "facebook/bart-large-cnn" {
+		t.Errorf("HuggingFace.SummarizationModel = %v, want %v", config.HuggingFace.SummarizationModel, "facebook/bart-large-cnn")
+	}
+	if config.HuggingFace.ModelInfoTTL != time.Hour {
+		t.Errorf("HuggingFace.ModelInfoTTL = %v, want %v", config.HuggingFace.ModelInfoTTL, time.Hour)
+	}
+	if config.HuggingFace.SummaryChunkSize != 3000 || config.HuggingFace.SummaryConcurrency != 4 {
+		t.Errorf("HuggingFace summary chunking = %v/%v, want 3000/4", config.HuggingFace.SummaryChunkSize, config.HuggingFace.SummaryConcurrency)
+	}
+	if breaker := config.HuggingFace.CircuitBreaker; breaker.ErrorRatio != 0.5 || breaker.MinRequests != 10 || breaker.Window != time.Minute || breaker.OpenTimeout != 30*time.Second {
+		t.Errorf("HuggingFace circuit breaker = %+v, want 0.5/10/1m/30s", breaker)
+	}
+	if retry := config.HuggingFace.Retry; retry.Attempts != 3 || retry.Delay != time.Second || retry.MaxDelay != 30*time.Second || retry.MaxWait != 2*time.Minute {
+		t.Errorf("HuggingFace retry = %+v, want 3/1s/30s/2m", retry)
+	}
+	if config.HuggingFace.QAWindowSize != 1500 || config.HuggingFace.QAWindowOverlap != 300 {
+		t.Errorf("HuggingFace QA windows = %v/%v, want 1500/300", config.HuggingFace.QAWindowSize, config.HuggingFace.QAWindowOverlap)
+	}
 }
 
 func TestLoadConfigMissingAPIKey(t *testing.T) {
@@ -214,6 +257,22 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid server port: -1",
 		},
+		{
+			name: "negative max body bytes",
+			config: Config{
+				Server: ServerConfig{
+					Port:         8080,
+					MaxBodyBytes: -1,
+				},
+				HuggingFace: HuggingFaceConfig{
+					APIKey:      "test-key",
+					MaxTokens:   100,
+					Temperature: 0.7,
+				},
+			},
+			wantErr: true,
+			errMsg:  "max body bytes must not be negative",
+		},
 		{
 			name: "invalid port - too high",
 			config: Config{
@@ -274,6 +333,71 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "temperature must be between 0 and 1",
 		},
+		{
+			name: "invalid QA window overlap",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				HuggingFace: HuggingFaceConfig{
+					APIKey:          "test-key",
+					MaxTokens:       100,
+					Temperature:     0.7,
+					QAWindowSize:    500,
+					QAWindowOverlap: 500,
+				},
+			},
+			wantErr: true,
+			errMsg:  "question answering window overlap must be between 0 and the window size",
+		},
+		{
+			name: "negative summary concurrency",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				HuggingFace: HuggingFaceConfig{
+					APIKey:             "test-key",
+					MaxTokens:          100,
+					Temperature:        0.7,
+					SummaryConcurrency: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "summary chunk size and concurrency must not be negative",
+		},
+		{
+			name: "invalid circuit breaker",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				HuggingFace: HuggingFaceConfig{
+					APIKey:         "test-key",
+					MaxTokens:      100,
+					Temperature:    0.7,
+					CircuitBreaker: CircuitBreakerConfig{ErrorRatio: 0.5},
+				},
+			},
+			wantErr: true,
+			errMsg:  "circuit breaker min requests, window and open timeout must be positive",
+		},
+		{
+			name: "invalid retry policy",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				HuggingFace: HuggingFaceConfig{
+					APIKey:        "test-key",
+					MaxTokens:     100,
+					Temperature:   0.7,
+					RetryPolicies: map[string]string{"gpt2": "tries:5"},
+				},
+			},
+			wantErr: true,
+			errMsg:  `retry policy "gpt2": unknown setting "tries"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -531,4 +655,332 @@ func TestGetEnvAsFloat32(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestGetEnvAsMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		want     map[string]string
+	}{
+		{
+			name:     "empty value",
+			envValue: "",
+			want:     map[string]string{},
+		},
+		{
+			name:     "single pair",
+			envValue: "gpt2=huggingface",
+			want:     map[string]string{"gpt2": "huggingface"},
+		},
+		{
+			name:     "multiple pairs with whitespace",
+			envValue: " llama-*=tgi , gpt-3.5-turbo = openai ",
+			want:     map[string]string{"llama-*": "tgi", "gpt-3.5-turbo": "openai"},
+		},
+		{
+			name:     "malformed pairs are skipped",
+			envValue: "novalue=,=nokey,missing,ok=yes",
+			want:     map[string]string{"ok": "yes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "TEST_MAP_VAR"
+			if tt.envValue != "" {
+				os.Setenv(key, tt.envValue)
+				defer os.Unsetenv(key)
+			}
+
+			got := getEnvAsMap(key)
+			if len(got) != len(tt.want) {
+				t.Fatalf("getEnvAsMap() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("getEnvAsMap()[%q] = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigWithProviders(t *testing.T) {
+	envVars := map[string]string{
+		"HUGGINGFACE_API_KEY": "test-api-key",
+		"TGI_BASE_URL":        "http://tgi:8080",
+		"OPENAI_BASE_URL":     "https://api.openai.com/v1",
+		"OPENAI_API_KEY":      "sk-test",
+		"MODEL_PROVIDERS":     "llama-*=tgi,gpt-3.5-turbo-instruct=openai",
+	}
+
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+
+	if config.Providers.Default != ProviderHuggingFace {
+		t.Errorf("Providers.Default = %v, want %v", config.Providers.Default, ProviderHuggingFace)
+	}
+	if config.Providers.TGI.BaseURL != "http://tgi:8080" {
+		t.Errorf("Providers.TGI.BaseURL = %v, want %v", config.Providers.TGI.BaseURL, "http://tgi:8080")
+	}
+	if config.Providers.OpenAI.APIKey != "sk-test" {
+		t.Errorf("Providers.OpenAI.APIKey = %v, want %v", config.Providers.OpenAI.APIKey, "sk-test")
+	}
+	if config.Providers.ModelRoutes["llama-*"] != ProviderTGI {
+		t.Errorf("Providers.ModelRoutes[llama-*] = %v, want %v", config.Providers.ModelRoutes["llama-*"], ProviderTGI)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error = %v", err)
+	}
+}
+
+func TestProvidersConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ProvidersConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "zero value",
+			config:  ProvidersConfig{},
+			wantErr: false,
+		},
+		{
+			name: "unknown default provider",
+			config: ProvidersConfig{
+				Default: "bedrock",
+			},
+			wantErr: true,
+			errMsg:  `default provider: unknown provider "bedrock"`,
+		},
+		{
+			name: "route to unconfigured tgi",
+			config: ProvidersConfig{
+				ModelRoutes: map[string]string{"llama-*": ProviderTGI},
+			},
+			wantErr: true,
+			errMsg:  `model route "llama-*": provider "tgi" requires TGI_BASE_URL`,
+		},
+		{
+			name: "route to unconfigured openai",
+			config: ProvidersConfig{
+				ModelRoutes: map[string]string{"gpt-4o": ProviderOpenAI},
+			},
+			wantErr: true,
+			errMsg:  `model route "gpt-4o": provider "openai" requires OPENAI_BASE_URL`,
+		},
+		{
+			name: "configured routes",
+			config: ProvidersConfig{
+				Default:     ProviderTGI,
+				TGI:         ProviderConfig{BaseURL: "http://tgi:8080"},
+				ModelRoutes: map[string]string{"gpt2": ProviderHuggingFace},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ProvidersConfig.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("ProvidersConfig.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("ProvidersConfig.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLookupModel(t *testing.T) {
+	patterns := map[string]string{
+		"gpt2":                 "exact",
+		"gpt2*":                "short-prefix",
+		"meta-llama/*":         "llama",
+		"meta-llama/Llama-2-*": "llama2",
+	}
+
+	tests := []struct {
+		model  string
+		want   string
+		wantOK bool
+	}{
+		{"gpt2", "exact", true},
+		{"gpt2-large", "short-prefix", true},
+		{"meta-llama/Llama-2-7b-chat-hf", "llama2", true},
+		{"meta-llama/Meta-Llama-3-8B", "llama", true},
+		{"facebook/bart-large-cnn", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got, ok := LookupModel(patterns, tt.model)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("LookupModel(%q) = (%v, %v), want (%v, %v)", tt.model, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadConfigWithAuth(t *testing.T) {
+	envVars := map[string]string{
+		"HUGGINGFACE_API_KEY":  "test-api-key",
+		"AUTH_ENABLED":         "true",
+		"AUTH_KEYS_FILE":       "/etc/go-ai/keys.json",
+		"CORS_ALLOWED_ORIGINS": "https://app.example.com, https://admin.example.com",
+	}
+
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+
+	if !config.Auth.Enabled {
+		t.Errorf("Auth.Enabled = false, want true")
+	}
+	if config.Auth.Source != AuthSourceFile {
+		t.Errorf("Auth.Source = %v, want %v", config.Auth.Source, AuthSourceFile)
+	}
+	wantOrigins := []string{"https://app.example.com", "https://admin.example.com"}
+	if !reflect.DeepEqual(config.Auth.AllowedOrigins, wantOrigins) {
+		t.Errorf("Auth.AllowedOrigins = %v, want %v", config.Auth.AllowedOrigins, wantOrigins)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error = %v", err)
+	}
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AuthConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "disabled",
+			config:  AuthConfig{Source: "ldap"},
+			wantErr: false,
+		},
+		{
+			name:    "file without path",
+			config:  AuthConfig{Enabled: true, Source: AuthSourceFile},
+			wantErr: true,
+			errMsg:  `auth source "file" requires AUTH_KEYS_FILE`,
+		},
+		{
+			name:    "file",
+			config:  AuthConfig{Enabled: true, Source: AuthSourceFile, KeysFile: "/etc/go-ai/keys.json"},
+			wantErr: false,
+		},
+		{
+			name:    "database",
+			config:  AuthConfig{Enabled: true, Source: "database"},
+			wantErr: true,
+			errMsg:  `unknown auth source "database"`,
+		},
+		{
+			name:    "unknown source",
+			config:  AuthConfig{Enabled: true, Source: "ldap"},
+			wantErr: true,
+			errMsg:  `unknown auth source "ldap"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("AuthConfig.validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("AuthConfig.validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("AuthConfig.validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	hf := HuggingFaceConfig{
+		Retry: RetryConfig{Attempts: 3, Delay: time.Second, MaxDelay: 30 * time.Second, MaxWait: 2 * time.Minute},
+		RetryPolicies: map[string]string{
+			"bigscience/*": "attempts:5; max_wait:10m",
+			"gpt2":         "attempts:0",
+			"broken":       "attempts:-1",
+		},
+	}
+
+	tests := []struct {
+		model string
+		want  RetryConfig
+	}{
+		{model: "bigscience/bloom", want: RetryConfig{Attempts: 5, Delay: time.Second, MaxDelay: 30 * time.Second, MaxWait: 10 * time.Minute}},
+		{model: "gpt2", want: RetryConfig{Attempts: 0, Delay: time.Second, MaxDelay: 30 * time.Second, MaxWait: 2 * time.Minute}},
+		{model: "broken", want: hf.Retry},
+		{model: "distilgpt2", want: hf.Retry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := hf.RetryPolicy(tt.model); got != tt.want {
+				t.Errorf("RetryPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	fallbacks := FallbackConfig{Models: map[string]string{
+		"gpt2-large":   "gpt2-medium | gpt2",
+		"meta-llama/*": "mistralai/Mistral-7B-Instruct-v0.2",
+	}}
+
+	tests := []struct {
+		model string
+		want  []string
+	}{
+		{model: "gpt2-large", want: []string{"gpt2-medium", "gpt2"}},
+		{model: "meta-llama/Llama-2-7b-chat-hf", want: []string{"mistralai/Mistral-7B-Instruct-v0.2"}},
+		{model: "gpt2", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := fallbacks.Chain(tt.model); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ModelsConfig holds the model registry: the models this server advertises,
+// with their task and defaults. Without a registry file the registry lists
+// the configured default model of every task.
+type ModelsConfig struct {
+	RegistryFile string        `json:"registry_file"`
+	Models       []ModelConfig `json:"models"`
+	TokenizerDir string        `json:"tokenizer_dir"` // holds <model>/tokenizer.json for exact token counts
+}
+
+// ModelConfig describes a registered model
+type ModelConfig struct {
+	ID               string                 `json:"id"`
+	Task             string                 `json:"task"`               // Hub pipeline tag, e.g. "text-generation"
+	Provider         string                 `json:"provider,omitempty"` // defaults to the provider the model is routed to
+	MaxContextLength int                    `json:"max_context_length,omitempty"`
+	Parameters       map[string]interface{} `json:"parameters,omitempty"` // default request parameters
+	Description      string                 `json:"description,omitempty"`
+}
+
+// Lookup returns the registered model with the given ID
+func (m *ModelsConfig) Lookup(id string) (*ModelConfig, bool) {
+	for i := range m.Models {
+		if m.Models[i].ID == id {
+			return &m.Models[i], true
+		}
+	}
+	return nil, false
+}
+
+// KnownModels returns a func reporting whether a model is configured: it is
+// registered, a task's default model, mapped to a translation pair or the
+// opus-mt model of one, routed to a provider, or part of a fallback chain
+func (c *Config) KnownModels() func(modelName string) bool {
+	patterns := map[string]string{"Helsinki-NLP/opus-mt-*": ""}
+	add := func(names ...string) {
+		for _, name := range names {
+			if name != "" {
+				patterns[name] = name
+			}
+		}
+	}
+
+	for _, entry := range c.Models.Models {
+		add(entry.ID)
+	}
+	hf := &c.HuggingFace
+	add(hf.DefaultModel, hf.SentimentModel, hf.SummarizationModel, hf.ZeroShotModel, hf.NERModel, hf.QAModel)
+	for _, modelName := range hf.TranslationModels {
+		add(modelName)
+	}
+	for pattern := range c.Providers.ModelRoutes {
+		add(pattern)
+	}
+	for pattern := range c.Fallbacks.Models {
+		add(pattern)
+		add(c.Fallbacks.Chain(pattern)...)
+	}
+	add(c.Fallbacks.Sentiment...)
+	add(c.Fallbacks.Summarization...)
+
+	return func(modelName string) bool {
+		_, ok := LookupModel(patterns, modelName)
+		return ok
+	}
+}
+
+// validate checks registry entries and their providers
+func (m *ModelsConfig) validate(providers *ProvidersConfig) error {
+	seen := make(map[string]bool, len(m.Models))
+	for i, entry := range m.Models {
+		if entry.ID == "" {
+			return fmt.Errorf("model registry entry %d: id is required", i)
+		}
+		if entry.Task == "" {
+			return fmt.Errorf("model %q: task is required", entry.ID)
+		}
+		if seen[entry.ID] {
+			return fmt.Errorf("model %q is registered more than once", entry.ID)
+		}
+		seen[entry.ID] = true
+		if entry.MaxContextLength < 0 {
+			return fmt.Errorf("model %q: max context length must not be negative", entry.ID)
+		}
+		if err := providers.checkProvider(entry.Provider); err != nil {
+			return fmt.Errorf("model %q: %w", entry.ID, err)
+		}
+	}
+	return nil
+}
+
+// ProviderFor returns the name of the provider that serves the given model
+func (p *ProvidersConfig) ProviderFor(modelName string) string {
+	if provider, ok := LookupModel(p.ModelRoutes, modelName); ok {
+		return provider
+	}
+	if p.Default == "" {
+		return ProviderHuggingFace
+	}
+	return p.Default
+}
+
+// loadModels reads a registry file of the form
+//
+//	{"models": [{"id": "gpt2", "task": "text-generation", "max_context_length": 1024}]}
+func loadModels(path string) ([]ModelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model registry: %w", err)
+	}
+
+	var file struct {
+		Models []ModelConfig `json:"models"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse model registry: %w", err)
+	}
+	return file.Models, nil
+}
+
+// defaultModels registers the configured default model of every task
+func defaultModels(hf *HuggingFaceConfig) []ModelConfig {
+	models := []ModelConfig{
+		{
+			ID:   hf.DefaultModel,
+			Task: "text-generation",
+			Parameters: map[string]interface{}{
+				"max_tokens":  hf.MaxTokens,
+				"temperature": hf.Temperature,
+			},
+		},
+		{ID: hf.SentimentModel, Task: "text-classification"},
+		{ID: hf.SummarizationModel, Task: "summarization", Parameters: map[string]interface{}{"max_length": 130}},
+		{ID: hf.ZeroShotModel, Task: "zero-shot-classification"},
+		{ID: hf.NERModel, Task: "token-classification", Parameters: map[string]interface{}{"aggregation_strategy": "simple"}},
+		{ID: hf.QAModel, Task: "question-answering"},
+	}
+	for _, id := range hf.TranslationModels {
+		models = append(models, ModelConfig{ID: id, Task: "translation"})
+	}
+
+	// Several tasks or language pairs may share a model
+	seen := make(map[string]bool, len(models))
+	unique := models[:0]
+	for _, entry := range models {
+		if entry.ID == "" || seen[entry.ID] {
+			continue
+		}
+		seen[entry.ID] = true
+		unique = append(unique, entry)
+	}
+	return unique
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigWithModelRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.json")
+	registry := `{"models": [
+		{"id": "gpt2", "task": "text-generation", "max_context_length": 1024, "parameters": {"temperature": 0.7}},
+		{"id": "llama-3-8b", "task": "text-generation", "max_context_length": 8192}
+	]}`
+	if err := os.WriteFile(path, []byte(registry), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	envVars := map[string]string{
+		"HUGGINGFACE_API_KEY": "test-api-key",
+		"MODEL_REGISTRY_FILE": path,
+		"MODEL_PROVIDERS":     "llama-*=tgi",
+		"TGI_BASE_URL":        "http://tgi:8080",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	if len(config.Models.Models) != 2 {
+		t.Fatalf("Models = %+v, want 2 entries", config.Models.Models)
+	}
+
+	gpt2, ok := config.Models.Lookup("gpt2")
+	if !ok || gpt2.Provider != ProviderHuggingFace || gpt2.MaxContextLength != 1024 || gpt2.Parameters["temperature"] != 0.7 {
+		t.Errorf("Lookup(gpt2) = %+v, want huggingface provider, 1024 context and temperature 0.7", gpt2)
+	}
+	llama, ok := config.Models.Lookup("llama-3-8b")
+	if !ok || llama.Provider != ProviderTGI {
+		t.Errorf("Lookup(llama-3-8b) = %+v, want provider %v", llama, ProviderTGI)
+	}
+	if _, ok := config.Models.Lookup("missing"); ok {
+		t.Errorf("Lookup(missing) = true, want false")
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error = %v", err)
+	}
+
+	os.Setenv("MODEL_REGISTRY_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := LoadConfig(); err == nil {
+		t.Errorf("LoadConfig() with a missing registry file expected error but got nil")
+	}
+}
+
+func TestDefaultModels(t *testing.T) {
+	hf := &HuggingFaceConfig{
+		DefaultModel:       "gpt2",
+		SentimentModel:     "sentiment-model",
+		SummarizationModel: "facebook/bart-large-cnn",
+		ZeroShotModel:      "facebook/bart-large-mnli",
+		NERModel:           "dslim/bert-base-NER",
+		QAModel:            "deepset/roberta-base-squad2",
+		TranslationModels: map[string]string{
+			"en-de": "Helsinki-NLP/opus-mt-en-de",
+			"en-*":  "facebook/m2m100_418M",
+			"fr-*":  "facebook/m2m100_418M",
+		},
+	}
+
+	models := defaultModels(hf)
+	if len(models) != 8 {
+		t.Errorf("defaultModels() returned %d models, want 8: %+v", len(models), models)
+	}
+	registry := ModelsConfig{Models: models}
+	for id, task := range map[string]string{
+		"gpt2":                 "text-generation",
+		"sentiment-model":      "text-classification",
+		"facebook/m2m100_418M": "translation",
+	} {
+		entry, ok := registry.Lookup(id)
+		if !ok || entry.Task != task {
+			t.Errorf("Lookup(%q) = %+v, want task %v", id, entry, task)
+		}
+	}
+}
+
+func TestModelsConfigValidate(t *testing.T) {
+	providers := &ProvidersConfig{}
+
+	tests := []struct {
+		name    string
+		models  []ModelConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid registry",
+			models:  []ModelConfig{{ID: "gpt2", Task: "text-generation", Provider: ProviderHuggingFace}},
+			wantErr: false,
+		},
+		{
+			name:    "missing id",
+			models:  []ModelConfig{{Task: "text-generation", Provider: ProviderHuggingFace}},
+			wantErr: true,
+			errMsg:  "model registry entry 0: id is required",
+		},
+		{
+			name:    "missing task",
+			models:  []ModelConfig{{ID: "gpt2", Provider: ProviderHuggingFace}},
+			wantErr: true,
+			errMsg:  `model "gpt2": task is required`,
+		},
+		{
+			name: "duplicate id",
+			models: []ModelConfig{
+				{ID: "gpt2", Task: "text-generation", Provider: ProviderHuggingFace},
+				{ID: "gpt2", Task: "text-generation", Provider: ProviderHuggingFace},
+			},
+			wantErr: true,
+			errMsg:  `model "gpt2" is registered more than once`,
+		},
+		{
+			name:    "unconfigured provider",
+			models:  []ModelConfig{{ID: "llama", Task: "text-generation", Provider: ProviderTGI}},
+			wantErr: true,
+			errMsg:  `model "llama": provider "tgi" requires TGI_BASE_URL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ModelsConfig{Models: tt.models}
+			err := m.validate(providers)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ModelsConfig.validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("ModelsConfig.validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("ModelsConfig.validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestKnownModels(t *testing.T) {
+	cfg := &Config{
+		HuggingFace: HuggingFaceConfig{
+			DefaultModel:      "gpt2",
+			SentimentModel:    "distilbert-sst2",
+			TranslationModels: map[string]string{"en-de": "facebook/nllb-200-distilled-600M"},
+		},
+		Providers: ProvidersConfig{ModelRoutes: map[string]string{"meta-llama/*": ProviderTGI}},
+		Fallbacks: FallbackConfig{
+			Models:    map[string]string{"gpt2-large": "gpt2-medium|gpt2"},
+			Sentiment: []string{"cardiffnlp/twitter-roberta"},
+		},
+		Models: ModelsConfig{Models: []ModelConfig{{ID: "mistralai/Mistral-7B", Task: "text-generation"}}},
+	}
+	known := cfg.KnownModels()
+
+	tests := []struct {
+		model string
+		want  bool
+	}{
+		{model: "mistralai/Mistral-7B", want: true},
+		{model: "gpt2", want: true},
+		{model: "distilbert-sst2", want: true},
+		{model: "facebook/nllb-200-distilled-600M", want: true},
+		{model: "Helsinki-NLP/opus-mt-en-fr", want: true},
+		{model: "meta-llama/Llama-2-7b", want: true},
+		{model: "gpt2-large", want: true},
+		{model: "gpt2-medium", want: true},
+		{model: "cardiffnlp/twitter-roberta", want: true},
+		{model: "made-up", want: false},
+		{model: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := known(tt.model); got != tt.want {
+			t.Errorf("KnownModels()(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
@@ -2,29 +2,92 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/cache"
+	"github.com/tusharr/go-ai-huggingface/internal/chat"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/jobs"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
 	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
 	"github.com/tusharr/go-ai-huggingface/pkg/logger"
 	"github.com/google/uuid"
 )
 
 // AIHandler handles AI-related HTTP requests
 type AIHandler struct {
-	aiService model.AIService
-	logger    logger.Logger
+	aiService      model.AIService
+	chatRenderer   *chat.Renderer
+	allowedOrigins []string
+	maxBodyBytes   int64
+	tasks          *config.HuggingFaceConfig
+	tokens         *tokenizer.Counter
+	batch          config.BatchConfig
+	jobs           *jobs.Manager
+	models         *config.ModelsConfig
+	breakers       func() []model.CircuitBreakerStatus
+	errorDetails   bool
+	metrics        *metrics.Metrics
+	logger         logger.Logger
+}
+
+// Option configures optional AIHandler dependencies
+type Option func(*AIHandler)
+
+// WithChatRenderer sets the chat template renderer used for conversations.
+// Without it every model uses the plain template.
+func WithChatRenderer(renderer *chat.Renderer) Option {
+	return func(h *AIHandler) {
+		h.chatRenderer = renderer
+	}
+}
+
+// WithTaskModels sets the configured task models. Question answering always
+// uses the configured model, which is checked against tenants' allowed models.
+func WithTaskModels(cfg *config.HuggingFaceConfig) Option {
+	return func(h *AIHandler) {
+		h.tasks = cfg
+	}
+}
+
+// WithTokenizer sets the token counter used for the prompt tokens of streams
+// cut short before the service reports usage. Without it they are estimated.
+func WithTokenizer(tokens *tokenizer.Counter) Option {
+	return func(h *AIHandler) {
+		h.tokens = tokens
+	}
 }
 
 // NewAIHandler creates a new AI handler
-func NewAIHandler(aiService model.AIService, logger logger.Logger) *AIHandler {
-	return &AIHandler{
-		aiService: aiService,
-		logger:    logger,
+func NewAIHandler(aiService model.AIService, metrics *metrics.Metrics, logger logger.Logger, opts ...Option) *AIHandler {
+	h := &AIHandler{
+		aiService:    aiService,
+		maxBodyBytes: defaultMaxBodyBytes,
+		batch:        config.BatchConfig{MaxSize: 100, Concurrency: 4},
+		metrics:      metrics,
+		logger:       logger,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.chatRenderer == nil {
+		h.chatRenderer, _ = chat.NewRenderer(&config.ChatConfig{})
 	}
+	return h
 }
 
 // GenerateText handles text generation requests
@@ -62,21 +125,24 @@ func (h *AIHandler) GenerateText(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	if wantsStream(r, &req) {
+		h.streamText(ctx, w, &req, nil)
+		return
+	}
+
 	// Generate text
 	response, err := h.aiService.GenerateText(ctx, &req)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to generate text", map[string]interface{}{
-			"error": err.Error(),
-		})
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to generate text",
-			Type:    "service_error",
-			Details: err.Error(),
-		})
+		h.handleServiceError(ctx, w, err, "Failed to generate text")
 		return
 	}
 
+	h.reconcileUsage(ctx, response.Usage)
 	h.sendJSONResponse(ctx, w, http.StatusOK, response)
 }
 
@@ -113,20 +179,23 @@ func (h *AIHandler) GenerateCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	if wantsStream(r, &req) {
+		h.streamText(ctx, w, &req, nil)
+		return
+	}
+
 	response, err := h.aiService.GenerateCompletion(ctx, &req)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to generate completion", map[string]interface{}{
-			"error": err.Error(),
-		})
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to generate completion",
-			Type:    "service_error",
-			Details: err.Error(),
-		})
+		h.handleServiceError(ctx, w, err, "Failed to generate completion")
 		return
 	}
 
+	h.reconcileUsage(ctx, response.Usage)
 	h.sendJSONResponse(ctx, w, http.StatusOK, response)
 }
 
@@ -135,10 +204,7 @@ func (h *AIHandler) AnalyzeSentiment(w http.ResponseWriter, r *http.Request) {
 	ctx := h.setRequestID(r.Context())
 	h.logger.Info(ctx, "Received sentiment analysis request", nil)
 
-	var req struct {
-		Text string `json:"text"`
-	}
-
+	var req model.SentimentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.handleError(ctx, w, &model.ErrorResponse{
 			Code:    http.StatusBadRequest,
@@ -148,26 +214,27 @@ func (h *AIHandler) AnalyzeSentiment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Text == "" {
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Text is required",
-			Type:    "validation_error",
-		})
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
 		return
 	}
 
-	response, err := h.aiService.AnalyzeSentiment(ctx, req.Text)
+	response, err := h.aiService.AnalyzeSentiment(ctx, &req)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to analyze sentiment", map[string]interface{}{
-			"error": err.Error(),
-		})
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to analyze sentiment",
-			Type:    "service_error",
-			Details: err.Error(),
-		})
+		h.handleServiceError(ctx, w, err, "Failed to analyze sentiment")
 		return
 	}
 
@@ -179,11 +246,7 @@ func (h *AIHandler) SummarizeText(w http.ResponseWriter, r *http.Request) {
 	ctx := h.setRequestID(r.Context())
 	h.logger.Info(ctx, "Received summarization request", nil)
 
-	var req struct {
-		Text      string `json:"text"`
-		MaxLength int    `json:"max_length,omitempty"`
-	}
-
+	var req model.SummaryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.handleError(ctx, w, &model.ErrorResponse{
 			Code:    http.StatusBadRequest,
@@ -193,31 +256,32 @@ func (h *AIHandler) SummarizeText(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Text == "" {
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Text is required",
-			Type:    "validation_error",
-		})
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
 		return
 	}
 
 	// Default max length
-	if req.MaxLength <= 0 {
+	if req.MaxLength == 0 {
 		req.MaxLength = 130
 	}
 
-	response, err := h.aiService.SummarizeText(ctx, req.Text, req.MaxLength)
+	response, err := h.aiService.SummarizeText(ctx, &req)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to summarize text", map[string]interface{}{
-			"error": err.Error(),
-		})
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to summarize text",
-			Type:    "service_error",
-			Details: err.Error(),
-		})
+		h.handleServiceError(ctx, w, err, "Failed to summarize text")
 		return
 	}
 
@@ -238,25 +302,61 @@ func (h *AIHandler) ValidateModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.aiService.ValidateModel(modelName)
+	if errResp := checkModelAccess(ctx, modelName); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	info, err := h.aiService.ValidateModel(ctx, modelName)
 	if err != nil {
-		h.handleError(ctx, w, &model.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: fmt.Sprintf("Invalid model: %v", err),
-			Type:    "validation_error",
+		var errResp *model.ErrorResponse
+		if errors.As(err, &errResp) {
+			h.handleError(ctx, w, errResp)
+			return
+		}
+		h.logger.Error(ctx, "Failed to validate model", map[string]interface{}{
+			"model": modelName,
+			"error": err.Error(),
 		})
+		errResp = &model.ErrorResponse{
+			Code:    http.StatusServiceUnavailable,
+			Message: "Model metadata is unavailable",
+			Type:    "service_error",
+		}
+		if h.errorDetails {
+			errResp.Details = err.Error()
+		}
+		h.handleError(ctx, w, errResp)
 		return
 	}
 
 	response := map[string]interface{}{
-		"model": modelName,
-		"valid": true,
+		"model":    modelName,
+		"valid":    true,
+		"metadata": info,
 	}
 
 	h.sendJSONResponse(ctx, w, http.StatusOK, response)
 }
 
-// Health handles health check requests
+// WithCircuitBreakers sets the source of the circuit breaker states reported
+// by Health
+func WithCircuitBreakers(statuses func() []model.CircuitBreakerStatus) Option {
+	return func(h *AIHandler) {
+		h.breakers = statuses
+	}
+}
+
+// WithErrorDetails includes the text of service errors, which may quote
+// upstream responses, in the details of error responses
+func WithErrorDetails(enabled bool) Option {
+	return func(h *AIHandler) {
+		h.errorDetails = enabled
+	}
+}
+
+// Health handles health check requests. The service is reported as degraded
+// while the circuit breaker of any model is open.
 func (h *AIHandler) Health(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"status":    "healthy",
@@ -264,23 +364,29 @@ func (h *AIHandler) Health(w http.ResponseWriter, r *http.Request) {
 		"service":   "go-ai-huggingface",
 		"version":   "1.0.0",
 	}
+	if h.breakers != nil {
+		breakers := h.breakers()
+		for _, breaker := range breakers {
+			if breaker.State != "closed" {
+				response["status"] = "degraded"
+			}
+		}
+		response["circuit_breakers"] = breakers
+	}
 
 	h.sendJSONResponse(r.Context(), w, http.StatusOK, response)
 }
 
-// Metrics handles metrics requests (basic implementation)
+// Metrics exposes collected metrics in the Prometheus text exposition format
 func (h *AIHandler) Metrics(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	
-	// In a production system, you'd collect real metrics
-	metrics := map[string]interface{}{
-		"requests_total":     1000, // Example counter
-		"requests_duration": "150ms", // Example histogram
-		"error_rate":        "0.01",  // Example gauge
-		"timestamp":         time.Now().UTC().Format(time.RFC3339),
-	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
 
-	h.sendJSONResponse(ctx, w, http.StatusOK, metrics)
+	if err := h.metrics.Write(w); err != nil {
+		h.logger.Error(r.Context(), "Failed to write metrics", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 }
 
 // setRequestID adds a request ID to the context if not already present
@@ -305,6 +411,32 @@ func (h *AIHandler) handleError(ctx context.Context, w http.ResponseWriter, errR
 	json.NewEncoder(w).Encode(errResp)
 }
 
+// serviceError converts an error returned by the AI service into the error
+// reported to the client, see ai.ErrorResponse. message describes failures
+// without a known cause, which are logged.
+func (h *AIHandler) serviceError(ctx context.Context, err error, message string) *model.ErrorResponse {
+	errResp := ai.ErrorResponse(err, message, h.errorDetails)
+	if errResp.Code == http.StatusInternalServerError {
+		h.logger.Error(ctx, message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return errResp
+}
+
+// setRetryAfter tells the client how long the upstream asked to wait when it did
+func setRetryAfter(w http.ResponseWriter, err error) {
+	if seconds := ai.RetryAfterSeconds(err); seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+}
+
+// handleServiceError responds to an error returned by the AI service
+func (h *AIHandler) handleServiceError(ctx context.Context, w http.ResponseWriter, err error, message string) {
+	setRetryAfter(w, err)
+	h.handleError(ctx, w, h.serviceError(ctx, err, message))
+}
+
 // sendJSONResponse sends a JSON response
 func (h *AIHandler) sendJSONResponse(ctx context.Context, w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -320,9 +452,14 @@ func (h *AIHandler) sendJSONResponse(ctx context.Context, w http.ResponseWriter,
 // CORS middleware
 func (h *AIHandler) EnableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if origin := h.corsOrigin(r.Header.Get("Origin")); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if origin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -333,6 +470,50 @@ func (h *AIHandler) EnableCORS(next http.Handler) http.Handler {
 	})
 }
 
+// CacheControl middleware lets clients bypass the response cache with
+// "Cache-Control: no-cache" (or no-store) and reports the cache outcome in the
+// X-Cache response header
+func (h *AIHandler) CacheControl(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		directives := strings.ToLower(r.Header.Get("Cache-Control"))
+		lookup := &cache.Lookup{
+			Bypass: strings.Contains(directives, "no-cache") || strings.Contains(directives, "no-store"),
+		}
+
+		wrapped := &cacheStatusWriter{ResponseWriter: w, lookup: lookup}
+		next.ServeHTTP(wrapped, r.WithContext(cache.WithLookup(r.Context(), lookup)))
+	})
+}
+
+// cacheStatusWriter sets the X-Cache header before the response is written
+type cacheStatusWriter struct {
+	http.ResponseWriter
+	lookup      *cache.Lookup
+	wroteHeader bool
+}
+
+func (cw *cacheStatusWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if cw.lookup.Status != "" {
+			cw.Header().Set("X-Cache", cw.lookup.Status)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheStatusWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (cw *cacheStatusWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
 // RequestLogger middleware
 func (h *AIHandler) RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -360,6 +541,21 @@ func (h *AIHandler) RequestLogger(next http.Handler) http.Handler {
 	})
 }
 
+// Instrument records request count, status and latency for a route. The route
+// label is the registered pattern rather than the raw path to keep cardinality bounded.
+func (h *AIHandler) Instrument(route string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		done := h.metrics.TrackInFlight()
+		defer done()
+
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r)
+
+		h.metrics.ObserveHTTPRequest(route, r.Method, wrapped.statusCode, time.Since(start))
+	})
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -371,6 +567,93 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController so that
+// streaming handlers can flush through the middleware chain
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// TokenRateLimiter middleware enforces a tokens-per-minute budget per API key
+// or client address. The estimated prompt size is charged before the request
+// runs; handlers reconcile the charge with the actual usage afterwards.
+func (h *AIHandler) TokenRateLimiter(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, ok := h.readBody(w, r)
+			if !ok {
+				return
+			}
+
+			reservation, status := limiter.Reserve(rateLimitKey(r), estimateTokens(string(body)))
+			w.Header().Set("X-RateLimit-Limit-Tokens", strconv.Itoa(status.Limit))
+			w.Header().Set("X-RateLimit-Remaining-Tokens", strconv.Itoa(status.Remaining))
+			w.Header().Set("X-RateLimit-Reset-Tokens", strconv.Itoa(ceilSeconds(status.Reset)))
+
+			if reservation == nil {
+				h.metrics.IncRateLimited("tokens_per_minute")
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(status.RetryAfter)))
+				h.handleError(r.Context(), w, &model.ErrorResponse{
+					Code:    http.StatusTooManyRequests,
+					Message: "Token rate limit exceeded",
+					Type:    "rate_limit_error",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ratelimit.WithReservation(r.Context(), reservation)))
+		})
+	}
+}
+
+// reconcileUsage settles the token rate limit charge for this request with
+// the usage reported by the service
+func (h *AIHandler) reconcileUsage(ctx context.Context, usage model.Usage) {
+	ratelimit.ReservationsFromContext(ctx).Reconcile(usage.TotalTokens)
+}
+
+// rateLimitKey identifies the client for rate limiting: the authenticated
+// tenant, else the API key when one is presented, otherwise the client address
+func rateLimitKey(r *http.Request) string {
+	if tenant := auth.TenantFromContext(r.Context()); tenant != nil {
+		return "tenant:" + tenant.ID
+	}
+	if header := r.Header.Get("Authorization"); header != "" {
+		sum := sha256.Sum256([]byte(header))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	return "ip:" + clientIP(r)
+}
+
+// clientIP returns the remote address without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// estimateTokens approximates the token count of text (1 token ≈ 4 characters)
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// LimitBody middleware rejects request bodies larger than the configured
+// limit with 413 before any handler reads them
+func (h *AIHandler) LimitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := h.readBody(w, r); !ok {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // RateLimiter middleware (basic implementation)
 func (h *AIHandler) RateLimiter(requestsPerMinute int) func(http.Handler) http.Handler {
 	// Simple in-memory rate limiter - in production use Redis or similar
@@ -379,7 +662,7 @@ func (h *AIHandler) RateLimiter(requestsPerMinute int) func(http.Handler) http.H
 	
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			clientIP := r.RemoteAddr
+			clientIP := clientIP(r)
 			now := time.Now()
 			
 			mu.Lock()
@@ -397,6 +680,7 @@ func (h *AIHandler) RateLimiter(requestsPerMinute int) func(http.Handler) http.H
 			// Check rate limit
 			if len(requests[clientIP]) >= requestsPerMinute {
 				mu.Unlock()
+				h.metrics.IncRateLimited("requests_per_minute")
 				h.handleError(r.Context(), w, &model.ErrorResponse{
 					Code:    http.StatusTooManyRequests,
 					Message: "Rate limit exceeded",
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestProviderErrors(t *testing.T) {
+	tgi := ai.NewTGIService(&config.ProviderConfig{BaseURL: "http://tgi.invalid"}, nil, metrics.New(), logger.NewNoopLogger())
+	h := NewAIHandler(tgi, metrics.New(), logger.NewNoopLogger())
+
+	tests := []struct {
+		name     string
+		serve    func(w http.ResponseWriter)
+		wantCode int
+		wantType string
+	}{
+		{
+			name: "unsupported operation",
+			serve: func(w http.ResponseWriter) {
+				h.AnalyzeSentiment(w, httptest.NewRequest(http.MethodPost, "/v1/sentiment", strings.NewReader(`{"text": "I love it"}`)))
+			},
+			wantCode: http.StatusNotImplemented,
+			wantType: ai.ErrorTypeUnsupported,
+		},
+		{
+			name: "empty model name",
+			serve: func(w http.ResponseWriter) {
+				ctx := context.Background()
+				_, err := tgi.ValidateModel(ctx, "")
+				h.handleServiceError(ctx, w, err, "Failed to validate model")
+			},
+			wantCode: http.StatusBadRequest,
+			wantType: "validation_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.serve(rec)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			var errResp model.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+				t.Fatalf("invalid error response: %v", err)
+			}
+			if errResp.Type != tt.wantType {
+				t.Errorf("error type = %q, want %q", errResp.Type, tt.wantType)
+			}
+		})
+	}
+}
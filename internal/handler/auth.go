@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+)
+
+// defaultMaxBodyBytes is the request body limit without WithMaxBodyBytes
+const defaultMaxBodyBytes = 1 << 20
+
+// WithAllowedOrigins sets the origins allowed by EnableCORS; "*" allows
+// every origin. Without it cross-origin requests are not allowed.
+func WithAllowedOrigins(origins []string) Option {
+	return func(h *AIHandler) {
+		h.allowedOrigins = origins
+	}
+}
+
+// WithMaxBodyBytes sets the largest request body accepted; 0 keeps the
+// default of 1 MiB
+func WithMaxBodyBytes(n int) Option {
+	return func(h *AIHandler) {
+		if n > 0 {
+			h.maxBodyBytes = int64(n)
+		}
+	}
+}
+
+// Authenticate middleware requires a valid API key, presented either as
+// "Authorization: Bearer <key>" or in the X-API-Key header. The key's tenant
+// is attached to the request context and charged against its request and
+// token quotas.
+func (h *AIHandler) Authenticate(store auth.Store, quotas *auth.Quotas) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			key := apiKey(r)
+			if key == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="go-ai-huggingface"`)
+				h.handleError(ctx, w, &model.ErrorResponse{
+					Code:    http.StatusUnauthorized,
+					Message: "API key is required",
+					Type:    "authentication_error",
+				})
+				return
+			}
+
+			tenant, err := store.Lookup(ctx, key)
+			if errors.Is(err, auth.ErrUnknownKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="go-ai-huggingface", error="invalid_token"`)
+				h.handleError(ctx, w, &model.ErrorResponse{
+					Code:    http.StatusUnauthorized,
+					Message: "Invalid API key",
+					Type:    "authentication_error",
+				})
+				return
+			}
+			if err != nil {
+				h.logger.Error(ctx, "Failed to look up API key", map[string]interface{}{
+					"error": err.Error(),
+				})
+				h.handleError(ctx, w, &model.ErrorResponse{
+					Code:    http.StatusServiceUnavailable,
+					Message: "Authentication is temporarily unavailable",
+					Type:    "service_error",
+				})
+				return
+			}
+
+			body, ok := h.readBody(w, r)
+			if !ok {
+				return
+			}
+
+			reservation, err := quotas.Reserve(tenant, estimateTokens(string(body)))
+			var quotaErr *auth.QuotaError
+			if errors.As(err, &quotaErr) {
+				h.metrics.IncRateLimited("tenant_" + quotaErr.Limit)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(quotaErr.RetryAfter)))
+				h.handleError(ctx, w, &model.ErrorResponse{
+					Code:    http.StatusTooManyRequests,
+					Message: fmt.Sprintf("Tenant quota exceeded (%s)", quotaErr.Limit),
+					Type:    "rate_limit_error",
+				})
+				return
+			}
+
+			ctx = auth.WithTenant(ctx, tenant)
+			if reservation != nil {
+				ctx = ratelimit.WithReservation(ctx, reservation)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// apiKey extracts the API key presented with the request
+func apiKey(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// checkModelAccess rejects models outside the authenticated tenant's allowed
+// list. Tenants restricted to a list must name the model explicitly.
+func checkModelAccess(ctx context.Context, modelName string) *model.ErrorResponse {
+	tenant := auth.TenantFromContext(ctx)
+	if tenant == nil || tenant.AllowsModel(modelName) {
+		return nil
+	}
+	message := fmt.Sprintf("Model %q is not allowed for this API key", modelName)
+	if modelName == "" {
+		message = "A model must be specified for this API key"
+	}
+	return &model.ErrorResponse{
+		Code:    http.StatusForbidden,
+		Message: message,
+		Type:    "model_not_allowed",
+	}
+}
+
+// readBody reads the request body, up to the body limit, and restores it for
+// the next handler. On failure it writes the error response and returns false.
+func (h *AIHandler) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	r.Body.Close()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.handleError(r.Context(), w, &model.ErrorResponse{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit),
+			Type:    "validation_error",
+		})
+		return nil, false
+	}
+	if err != nil {
+		h.handleError(r.Context(), w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to read request body",
+			Type:    "validation_error",
+		})
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+// corsOrigin returns the Access-Control-Allow-Origin value for a request
+// origin, or "" when the origin is not allowed
+func (h *AIHandler) corsOrigin(origin string) string {
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func TestBodyLimit(t *testing.T) {
+	h := NewAIHandler(mocks.NewMockAIService(), metrics.New(), logger.NewNoopLogger(), WithMaxBodyBytes(16))
+	middlewares := map[string]func(http.Handler) http.Handler{
+		"LimitBody":        h.LimitBody,
+		"TokenRateLimiter": h.TokenRateLimiter(ratelimit.NewLimiter(10000)),
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "within limit", body: `{"prompt": "Hi"}`, wantCode: http.StatusOK},
+		{name: "over limit", body: `{"prompt": "Hello there"}`, wantCode: http.StatusRequestEntityTooLarge},
+	}
+
+	for name, middleware := range middlewares {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				var got string
+				next := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					got = string(body)
+				}))
+
+				rec := httptest.NewRecorder()
+				next.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/text/generate", strings.NewReader(tt.body)))
+				if rec.Code != tt.wantCode {
+					t.Fatalf("%s status = %d, want %d", name, rec.Code, tt.wantCode)
+				}
+				if tt.wantCode == http.StatusOK && got != tt.body {
+					t.Errorf("%s passed body %q, want %q", name, got, tt.body)
+				}
+				if tt.wantCode != http.StatusOK && got != "" {
+					t.Errorf("%s reached the handler with body %q", name, got)
+				}
+			})
+		}
+	}
+}
+
+func TestEnableCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		want    string
+	}{
+		{name: "no allowed origins", origin: "https://app.example.com", want: ""},
+		{name: "allowed origin", origins: []string{"https://app.example.com"}, origin: "https://app.example.com", want: "https://app.example.com"},
+		{name: "other origin", origins: []string{"https://app.example.com"}, origin: "https://evil.example.com", want: ""},
+		{name: "any origin", origins: []string{"*"}, origin: "https://evil.example.com", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAIHandler(mocks.NewMockAIService(), metrics.New(), logger.NewNoopLogger(), WithAllowedOrigins(tt.origins))
+			next := h.EnableCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+			req.Header.Set("Origin", tt.origin)
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, req)
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelAccessWithoutModel(t *testing.T) {
+	h := NewAIHandler(mocks.NewMockAIService(), metrics.New(), logger.NewNoopLogger(),
+		WithTaskModels(&config.HuggingFaceConfig{QAModel: "deepset/roberta-base-squad2"}))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+	}{
+		{name: "sentiment", handler: h.AnalyzeSentiment, body: `{"text": "I love it"}`},
+		{name: "summarization", handler: h.SummarizeText, body: `{"text": "A long text"}`},
+		{name: "classification", handler: h.ClassifyText, body: `{"text": "My card was charged twice", "labels": ["billing", "shipping"]}`},
+		{name: "entities", handler: h.ExtractEntities, body: `{"text": "Ada Lovelace was born in London"}`},
+		{name: "translation", handler: h.TranslateText, body: `{"text": "Hello", "source_lang": "en", "target_lang": "fr"}`},
+		{name: "question answering", handler: h.AnswerQuestion, body: `{"question": "Who?", "context": "Ada wrote it"}`},
+		{name: "task job", handler: h.CreateJob, body: `{"type": "sentiment", "input": {"text": "I love it"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, tenant := range []*auth.Tenant{
+				{ID: "restricted", AllowedModels: []string{"gpt2"}},
+				{ID: "unrestricted"},
+			} {
+				if tt.name == "task job" && tenant.AllowedModels == nil {
+					continue // needs a job manager
+				}
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+				req = req.WithContext(auth.WithTenant(req.Context(), tenant))
+				rec := httptest.NewRecorder()
+				tt.handler(rec, req)
+
+				wantForbidden := tenant.AllowedModels != nil
+				if forbidden := rec.Code == http.StatusForbidden; forbidden != wantForbidden {
+					t.Errorf("%s tenant: status = %d, want forbidden %v: %s", tenant.ID, rec.Code, wantForbidden, rec.Body.String())
+				}
+			}
+		})
+	}
+
+	// A tenant allowed the configured question answering model may use it
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"question": "Who?", "context": "Ada wrote it"}`))
+	req = req.WithContext(auth.WithTenant(req.Context(), &auth.Tenant{ID: "qa", AllowedModels: []string{"deepset/*"}}))
+	rec := httptest.NewRecorder()
+	h.AnswerQuestion(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("AnswerQuestion() status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
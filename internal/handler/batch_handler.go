@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// WithBatchConfig sets the batch size limit and the number of batch items
+// generated concurrently. Without it batches are limited to 100 items run
+// 4 at a time.
+func WithBatchConfig(cfg *config.BatchConfig) Option {
+	return func(h *AIHandler) {
+		h.batch = *cfg
+	}
+}
+
+// GenerateBatch handles batch generation requests. Items are generated
+// concurrently by a bounded worker pool and reported individually in request
+// order, so a failing item does not fail the rest of the batch.
+func (h *AIHandler) GenerateBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received batch generation request", nil)
+
+	var req model.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if err := req.Validate(h.batch.MaxSize); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+
+	start := time.Now()
+	results := h.runBatch(ctx, req.Requests)
+
+	response := &model.BatchResponse{
+		ID:           req.ID,
+		Results:      results,
+		GeneratedAt:  time.Now(),
+		ProcessingMs: time.Since(start).Milliseconds(),
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			response.Failed++
+			continue
+		}
+		response.Succeeded++
+		response.Usage.PromptTokens += result.Response.Usage.PromptTokens
+		response.Usage.CompletionTokens += result.Response.Usage.CompletionTokens
+		response.Usage.TotalTokens += result.Response.Usage.TotalTokens
+	}
+
+	h.logger.Info(ctx, "Batch generation completed", map[string]interface{}{
+		"batch_id":  req.ID,
+		"succeeded": response.Succeeded,
+		"failed":    response.Failed,
+	})
+
+	h.reconcileUsage(ctx, response.Usage)
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// runBatch generates every request with at most h.batch.Concurrency in
+// flight and returns the results in request order
+func (h *AIHandler) runBatch(ctx context.Context, requests []model.AIRequest) []model.BatchResult {
+	results := make([]model.BatchResult, len(requests))
+
+	workers := h.batch.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = h.generateBatchItem(ctx, index, &requests[index])
+			}
+		}()
+	}
+
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// generateBatchItem validates and generates a single batch item
+func (h *AIHandler) generateBatchItem(ctx context.Context, index int, req *model.AIRequest) model.BatchResult {
+	result := model.BatchResult{Index: index}
+
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+	req.CreatedAt = time.Now()
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			result.Error = errResp
+		} else {
+			result.Error = &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			}
+		}
+		return result
+	}
+	if req.Stream {
+		result.Error = &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "streaming is not supported in batch requests",
+			Type:    "validation_error",
+		}
+		return result
+	}
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		result.Error = errResp
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		result.Error = &model.ErrorResponse{
+			Code:    http.StatusServiceUnavailable,
+			Message: "Batch request was cancelled",
+			Type:    "service_error",
+			Details: err.Error(),
+		}
+		return result
+	}
+
+	response, err := h.aiService.GenerateText(ctx, req)
+	if err != nil {
+		result.Error = h.serviceError(ctx, err, "Failed to generate text")
+		return result
+	}
+
+	result.Response = response
+	return result
+}
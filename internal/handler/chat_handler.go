@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// Chat handles multi-turn conversation requests. Messages are rendered into a
+// prompt with the chat template configured for the model.
+func (h *AIHandler) Chat(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received chat request", nil)
+
+	var req model.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+	req.CreatedAt = time.Now()
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	prompt, stops := h.chatRenderer.Render(req.Model, req.Messages)
+	aiReq := req.ToAIRequest(prompt)
+	aiReq.Parameters = withStopSequences(req.Parameters, stops)
+
+	if wantsStream(r, aiReq) {
+		h.streamText(ctx, w, aiReq, stops)
+		return
+	}
+
+	response, err := h.aiService.GenerateText(ctx, aiReq)
+	if err != nil {
+		h.handleServiceError(ctx, w, err, "Failed to generate chat reply")
+		return
+	}
+
+	h.reconcileUsage(ctx, response.Usage)
+	chatResp := &model.ChatResponse{
+		ID:           response.ID,
+		Model:        response.Model,
+		Usage:        response.Usage,
+		GeneratedAt:  response.GeneratedAt,
+		ProcessingMs: response.ProcessingMs,
+		Message:      model.ChatMessage{Role: model.RoleAssistant},
+	}
+	if len(response.Choices) > 0 {
+		text, stopped := applyStopSequences(response.Choices[0].Text, stops)
+		chatResp.Message.Content = text
+		chatResp.FinishReason = response.Choices[0].FinishReason
+		if stopped {
+			chatResp.FinishReason = "stop"
+		}
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, chatResp)
+}
+
+// withStopSequences returns a copy of params with the stop sequences merged
+// into the "stop" parameter
+func withStopSequences(params map[string]interface{}, stops []string) map[string]interface{} {
+	if len(stops) == 0 {
+		return params
+	}
+
+	merged := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		merged[k] = v
+	}
+
+	all := append([]string(nil), stops...)
+	switch existing := merged["stop"].(type) {
+	case string:
+		all = append(all, existing)
+	case []interface{}:
+		for _, s := range existing {
+			if str, ok := s.(string); ok {
+				all = append(all, str)
+			}
+		}
+	case []string:
+		all = append(all, existing...)
+	}
+	merged["stop"] = all
+	return merged
+}
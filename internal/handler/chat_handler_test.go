@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/chat"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func TestChatStreamStopSequences(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []model.StreamChunk
+		want   string
+	}{
+		{
+			name:   "stop sequence in one token",
+			tokens: []model.StreamChunk{{Text: "Hello"}, {Text: " there"}, {Text: "<|im_end|>"}, {Text: "\n<|im_start|>user"}},
+			want:   "Hello there",
+		},
+		{
+			name:   "stop sequence split across tokens",
+			tokens: []model.StreamChunk{{Text: "Hello"}, {Text: " there<|im"}, {Text: "_e"}, {Text: "nd|>\nuser"}},
+			want:   "Hello there",
+		},
+		{
+			name:   "special stop token",
+			tokens: []model.StreamChunk{{Text: "Hello"}, {Text: "<|im_end|>", Special: true}, {Text: " more"}},
+			want:   "Hello",
+		},
+		{
+			name:   "no stop sequence",
+			tokens: []model.StreamChunk{{Text: "Hello"}, {Text: " <|im"}, {Text: " there"}},
+			want:   "Hello <|im there",
+		},
+	}
+
+	renderer, err := chat.NewRenderer(&config.ChatConfig{DefaultTemplate: "chatml"})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockAIService()
+			mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+				var text strings.Builder
+				for i, token := range tt.tokens {
+					token.ID, token.Model, token.Index = req.ID, req.Model, i
+					if err := fn(&token); err != nil {
+						return nil, err
+					}
+					if !token.Special {
+						text.WriteString(token.Text)
+					}
+				}
+				return &model.AIResponse{ID: req.ID, Model: req.Model, Choices: []model.Choice{{Text: text.String(), FinishReason: "length"}}}, nil
+			}
+			h := NewAIHandler(mock, metrics.New(), logger.NewNoopLogger(), WithChatRenderer(renderer))
+
+			body := `{"model": "gpt2", "stream": true, "messages": [{"role": "user", "content": "Hi"}]}`
+			rec := httptest.NewRecorder()
+			h.Chat(rec, httptest.NewRequest(http.MethodPost, "/v1/chat", strings.NewReader(body)))
+
+			if strings.Contains(rec.Body.String(), "<|im_end") {
+				t.Errorf("Chat() streamed the stop sequence: %s", rec.Body.String())
+			}
+
+			var content strings.Builder
+			var usage *model.AIResponse
+			var event string
+			scanner := bufio.NewScanner(rec.Body)
+			for scanner.Scan() {
+				if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+					event = name
+					continue
+				}
+				data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+				if !ok || data == "[DONE]" {
+					continue
+				}
+				switch event {
+				case "token":
+					var chunk model.StreamChunk
+					if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+						t.Fatalf("invalid token event %q: %v", data, err)
+					}
+					if !chunk.Special {
+						content.WriteString(chunk.Text)
+					}
+				case "usage":
+					usage = &model.AIResponse{}
+					if err := json.Unmarshal([]byte(data), usage); err != nil {
+						t.Fatalf("invalid usage event %q: %v", data, err)
+					}
+				}
+			}
+
+			if content.String() != tt.want {
+				t.Errorf("Chat() streamed %q, want %q", content.String(), tt.want)
+			}
+			if usage == nil || len(usage.Choices) == 0 {
+				t.Fatalf("Chat() sent no usage event: %s", rec.Body.String())
+			}
+			if usage.Choices[0].Text != tt.want {
+				t.Errorf("usage text = %q, want %q", usage.Choices[0].Text, tt.want)
+			}
+		})
+	}
+}
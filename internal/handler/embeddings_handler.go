@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// Embeddings handles embedding requests. Request and response follow the
+// OpenAI embeddings API, extended with the normalize and pooling options.
+func (h *AIHandler) Embeddings(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received embeddings request", nil)
+
+	var req model.EmbeddingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleOpenAIError(ctx, w, errResp)
+		} else {
+			h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleOpenAIError(ctx, w, errResp)
+		return
+	}
+
+	response, err := h.aiService.Embed(ctx, &req)
+	if err != nil {
+		setRetryAfter(w, err)
+		h.handleOpenAIError(ctx, w, h.serviceError(ctx, err, "Failed to compute embeddings"))
+		return
+	}
+
+	h.reconcileUsage(ctx, response.Usage)
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/jobs"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// WithJobs enables the asynchronous job endpoints
+func WithJobs(manager *jobs.Manager) Option {
+	return func(h *AIHandler) {
+		h.jobs = manager
+	}
+}
+
+// CreateJob handles requests to run an operation asynchronously. The job ID
+// is returned immediately; the result is available from GetJob and, when a
+// callback URL is given, posted to it.
+func (h *AIHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received job request", nil)
+
+	var req model.JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	// Tenants restricted to a list of models must name the model of every
+	// job, including task jobs that would otherwise use the configured default
+	var input struct {
+		Model string `json:"model"`
+	}
+	json.Unmarshal(req.Input, &input)
+	if errResp := checkModelAccess(ctx, input.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	job, err := h.jobs.Submit(ctx, &req)
+	if err != nil {
+		var errResp *model.ErrorResponse
+		switch {
+		case errors.As(err, &errResp):
+			h.handleError(ctx, w, errResp)
+		case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, jobs.ErrClosed):
+			w.Header().Set("Retry-After", "1")
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusServiceUnavailable,
+				Message: "Job could not be queued",
+				Type:    "service_error",
+				Details: err.Error(),
+			})
+		default:
+			h.logger.Error(ctx, "Failed to submit job", map[string]interface{}{
+				"error": err.Error(),
+			})
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to submit job",
+				Type:    "service_error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	h.sendJSONResponse(ctx, w, http.StatusAccepted, job)
+}
+
+// GetJob handles job status requests. Jobs are only visible to the tenant
+// that created them.
+func (h *AIHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	id := r.PathValue("id")
+
+	job, err := h.jobs.Get(ctx, id)
+	if err == nil {
+		if tenant := auth.TenantFromContext(ctx); tenant != nil && tenant.ID != job.TenantID {
+			err = jobs.ErrNotFound
+		}
+	}
+	if errors.Is(err, jobs.ErrNotFound) {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Job %q not found", id),
+			Type:    "not_found",
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load job", map[string]interface{}{
+			"error": err.Error(),
+		})
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to load job",
+			Type:    "service_error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, job)
+}
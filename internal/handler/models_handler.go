@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// WithModels sets the model registry served by ListModels and GetModel.
+// Without it no models are listed.
+func WithModels(cfg *config.ModelsConfig) Option {
+	return func(h *AIHandler) {
+		h.models = cfg
+	}
+}
+
+// ListModels handles model listing requests. Only the models the caller's
+// tenant may use are listed.
+func (h *AIHandler) ListModels(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	tenant := auth.TenantFromContext(ctx)
+
+	list := model.ModelList{Object: "list", Data: []model.RegisteredModel{}}
+	if h.models != nil {
+		for i := range h.models.Models {
+			entry := &h.models.Models[i]
+			if tenant != nil && !tenant.AllowsModel(entry.ID) {
+				continue
+			}
+			list.Data = append(list.Data, registeredModel(entry))
+		}
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, list)
+}
+
+// GetModel handles requests for a single registered model. Models the
+// caller's tenant may not use are reported as not found.
+func (h *AIHandler) GetModel(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	id := r.PathValue("id")
+
+	var entry *config.ModelConfig
+	if h.models != nil {
+		entry, _ = h.models.Lookup(id)
+	}
+	if tenant := auth.TenantFromContext(ctx); entry != nil && tenant != nil && !tenant.AllowsModel(id) {
+		entry = nil
+	}
+	if entry == nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Model %q not found", id),
+			Type:    "not_found",
+		})
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, registeredModel(entry))
+}
+
+func registeredModel(entry *config.ModelConfig) model.RegisteredModel {
+	return model.RegisteredModel{
+		ID:               entry.ID,
+		Object:           "model",
+		Task:             entry.Task,
+		Provider:         entry.Provider,
+		MaxContextLength: entry.MaxContextLength,
+		Parameters:       entry.Parameters,
+		Description:      entry.Description,
+	}
+}
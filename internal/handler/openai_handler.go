@@ -0,0 +1,458 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// maxOpenAIChoices bounds the n parameter since each choice is a separate generation
+const maxOpenAIChoices = 8
+
+// errStopSequence aborts a stream once a stop sequence has been produced
+var errStopSequence = errors.New("stop sequence reached")
+
+// openAIGeneration holds a translated OpenAI request
+type openAIGeneration struct {
+	req    model.AIRequest
+	n      int
+	stop   []string
+	stream bool
+	chat   bool
+	id     string
+}
+
+// ChatCompletions handles OpenAI-compatible chat completion requests
+func (h *AIHandler) ChatCompletions(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received OpenAI chat completion request", nil)
+
+	var oaReq model.OpenAIChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&oaReq); err != nil {
+		h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	chatReq := model.ChatRequest{
+		Model:    oaReq.Model,
+		Messages: oaReq.Messages,
+	}
+	if err := chatReq.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleOpenAIError(ctx, w, errResp)
+		} else {
+			h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	prompt, templateStops := h.chatRenderer.Render(oaReq.Model, oaReq.Messages)
+	gen := &openAIGeneration{
+		req: model.AIRequest{
+			Model:     oaReq.Model,
+			Prompt:    prompt,
+			MaxTokens: oaReq.MaxTokens,
+			TopP:      oaReq.TopP,
+		},
+		n:      oaReq.N,
+		stop:   append(oaReq.Stop, templateStops...),
+		stream: oaReq.Stream,
+		chat:   true,
+		id:     "chatcmpl-" + uuid.New().String(),
+	}
+	if oaReq.Temperature != nil {
+		gen.req.SetTemperature(float32(*oaReq.Temperature))
+	}
+	if oaReq.User != "" {
+		h.logger.Info(ctx, "OpenAI request user", map[string]interface{}{"user": oaReq.User})
+	}
+
+	h.serveOpenAI(ctx, w, gen)
+}
+
+// Completions handles OpenAI-compatible text completion requests
+func (h *AIHandler) Completions(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received OpenAI completion request", nil)
+
+	var oaReq model.OpenAICompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&oaReq); err != nil {
+		h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	gen := &openAIGeneration{
+		req: model.AIRequest{
+			Model:     oaReq.Model,
+			Prompt:    oaReq.Prompt,
+			MaxTokens: oaReq.MaxTokens,
+			TopP:      oaReq.TopP,
+		},
+		n:      oaReq.N,
+		stop:   oaReq.Stop,
+		stream: oaReq.Stream,
+		id:     "cmpl-" + uuid.New().String(),
+	}
+	if oaReq.Temperature != nil {
+		gen.req.SetTemperature(float32(*oaReq.Temperature))
+	}
+	if oaReq.User != "" {
+		h.logger.Info(ctx, "OpenAI request user", map[string]interface{}{"user": oaReq.User})
+	}
+
+	h.serveOpenAI(ctx, w, gen)
+}
+
+// serveOpenAI validates a translated request and runs it in streaming or
+// blocking mode
+func (h *AIHandler) serveOpenAI(ctx context.Context, w http.ResponseWriter, gen *openAIGeneration) {
+	gen.req.ID = gen.id
+	gen.req.CreatedAt = time.Now()
+	if len(gen.stop) > 0 {
+		gen.req.Parameters = map[string]interface{}{"stop": gen.stop}
+	}
+	if gen.n == 0 {
+		gen.n = 1
+	}
+
+	if gen.n < 0 || gen.n > maxOpenAIChoices {
+		h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("n must be between 1 and %d", maxOpenAIChoices),
+			Type:    "validation_error",
+		})
+		return
+	}
+	if gen.stream && gen.n > 1 {
+		h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "n greater than 1 is not supported when streaming",
+			Type:    "validation_error",
+		})
+		return
+	}
+	if err := gen.req.ValidateWithin(model.MaxTemperature); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleOpenAIError(ctx, w, errResp)
+		} else {
+			h.handleOpenAIError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+	if errResp := checkModelAccess(ctx, gen.req.Model); errResp != nil {
+		h.handleOpenAIError(ctx, w, errResp)
+		return
+	}
+
+	if gen.stream {
+		h.streamOpenAI(ctx, w, gen)
+		return
+	}
+
+	var texts, reasons []string
+	usage := model.OpenAIUsage{}
+	servedBy := gen.req.Model // a fallback model may answer instead
+	for i := 0; i < gen.n; i++ {
+		response, err := h.aiService.GenerateText(ctx, &gen.req)
+		if err != nil {
+			setRetryAfter(w, err)
+			h.handleOpenAIError(ctx, w, h.serviceError(ctx, err, "Failed to generate completion"))
+			return
+		}
+		if response.Model != "" {
+			servedBy = response.Model
+		}
+
+		for _, choice := range response.Choices {
+			text, stopped := applyStopSequences(choice.Text, gen.stop)
+			texts = append(texts, text)
+			reasons = append(reasons, openAIFinishReason(choice.FinishReason, stopped))
+		}
+		usage.PromptTokens = response.Usage.PromptTokens
+		usage.CompletionTokens += response.Usage.CompletionTokens
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	h.reconcileUsage(ctx, model.Usage(usage))
+
+	created := time.Now().Unix()
+	if gen.chat {
+		resp := &model.OpenAIChatCompletionResponse{
+			ID:      gen.id,
+			Object:  "chat.completion",
+			Created: created,
+			Model:   servedBy,
+			Usage:   usage,
+		}
+		for i, text := range texts {
+			resp.Choices = append(resp.Choices, model.OpenAIChatChoice{
+				Index:        i,
+				Message:      model.ChatMessage{Role: model.RoleAssistant, Content: text},
+				FinishReason: reasons[i],
+			})
+		}
+		h.sendJSONResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+
+	resp := &model.OpenAICompletionResponse{
+		ID:      gen.id,
+		Object:  "text_completion",
+		Created: created,
+		Model:   servedBy,
+		Usage:   &usage,
+	}
+	for i, text := range texts {
+		reason := reasons[i]
+		resp.Choices = append(resp.Choices, model.OpenAICompletionChoice{
+			Index:        i,
+			Text:         text,
+			FinishReason: &reason,
+		})
+	}
+	h.sendJSONResponse(ctx, w, http.StatusOK, resp)
+}
+
+// streamOpenAI streams a generation as OpenAI-style chunks. Output goes
+// through a stopFilter so that a stop sequence split across tokens is never
+// sent to the client.
+func (h *AIHandler) streamOpenAI(ctx context.Context, w http.ResponseWriter, gen *openAIGeneration) {
+	created := time.Now().Unix()
+
+	var sse *sseWriter
+	filter := newStopFilter(gen.stop)
+	servedBy := gen.req.Model // the model reported by the service, which may be a fallback
+	completionTokens := 0
+
+	emit := func(text string) error {
+		if text == "" {
+			return nil
+		}
+		if gen.chat {
+			return sse.send("", &model.OpenAIChatCompletionChunk{
+				ID:      gen.id,
+				Object:  "chat.completion.chunk",
+				Created: created,
+				Model:   servedBy,
+				Choices: []model.OpenAIChatChunkChoice{{Delta: model.OpenAIChatDelta{Content: text}}},
+			})
+		}
+		return sse.send("", &model.OpenAICompletionResponse{
+			ID:      gen.id,
+			Object:  "text_completion",
+			Created: created,
+			Model:   servedBy,
+			Choices: []model.OpenAICompletionChoice{{Text: text}},
+		})
+	}
+
+	start := func() error {
+		sse = newSSEWriter(w)
+		if !gen.chat {
+			return nil
+		}
+		return sse.send("", &model.OpenAIChatCompletionChunk{
+			ID:      gen.id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   servedBy,
+			Choices: []model.OpenAIChatChunkChoice{{Delta: model.OpenAIChatDelta{Role: model.RoleAssistant}}},
+		})
+	}
+
+	response, err := h.aiService.GenerateTextStream(ctx, &gen.req, func(chunk *model.StreamChunk) error {
+		if chunk.Model != "" {
+			servedBy = chunk.Model
+		}
+		if sse == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if chunk.Special {
+			if filter.special(chunk.Text) {
+				return errStopSequence
+			}
+			return nil
+		}
+		completionTokens++
+
+		if err := emit(filter.write(chunk.Text)); err != nil {
+			return err
+		}
+		if filter.stopped {
+			return errStopSequence
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errStopSequence) {
+		if ctx.Err() != nil {
+			h.logger.Info(ctx, "Client disconnected during stream", map[string]interface{}{
+				"request_id": gen.id,
+			})
+			return
+		}
+		h.logger.Error(ctx, "Failed to stream OpenAI completion", map[string]interface{}{
+			"error": err.Error(),
+		})
+		errResp := h.serviceError(ctx, err, "Failed to generate completion")
+		if sse == nil {
+			setRetryAfter(w, err)
+			h.handleOpenAIError(ctx, w, errResp)
+			return
+		}
+		sse.send("", openAIErrorBody(errResp))
+		sse.done()
+		return
+	}
+
+	if sse == nil {
+		if err := start(); err != nil {
+			return
+		}
+	}
+
+	// Flush any held-back text
+	if err := emit(filter.flush()); err != nil {
+		return
+	}
+
+	usage := &model.OpenAIUsage{
+		PromptTokens:     h.tokens.CountInput(gen.req.Model, gen.req.Prompt),
+		CompletionTokens: completionTokens,
+	}
+	finishReason := "stop"
+	if response != nil {
+		if response.Model != "" {
+			servedBy = response.Model
+		}
+		usage.PromptTokens = response.Usage.PromptTokens
+		usage.CompletionTokens = response.Usage.CompletionTokens
+		if len(response.Choices) > 0 {
+			finishReason = openAIFinishReason(response.Choices[0].FinishReason, filter.stopped)
+		}
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	h.reconcileUsage(ctx, model.Usage(*usage))
+
+	if gen.chat {
+		sse.send("", &model.OpenAIChatCompletionChunk{
+			ID:      gen.id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   servedBy,
+			Choices: []model.OpenAIChatChunkChoice{{Delta: model.OpenAIChatDelta{}, FinishReason: &finishReason}},
+			Usage:   usage,
+		})
+	} else {
+		sse.send("", &model.OpenAICompletionResponse{
+			ID:      gen.id,
+			Object:  "text_completion",
+			Created: created,
+			Model:   servedBy,
+			Choices: []model.OpenAICompletionChoice{{FinishReason: &finishReason}},
+			Usage:   usage,
+		})
+	}
+	sse.done()
+}
+
+// runeBoundary moves i back to the start of the UTF-8 character it falls in,
+// so that text[:i] never ends in a partial character. Token boundaries and the
+// stop sequence hold-back are counted in bytes.
+func runeBoundary(text string, i int) int {
+	for j := i; j > 0 && i-j < utf8.UTFMax; {
+		j--
+		if utf8.RuneStart(text[j]) {
+			if !utf8.FullRuneInString(text[j:i]) {
+				return j
+			}
+			return i
+		}
+	}
+	return i
+}
+
+// applyStopSequences truncates text at the first stop sequence and reports
+// whether one was found
+func applyStopSequences(text string, stops []string) (string, bool) {
+	cut := -1
+	for _, stop := range stops {
+		if stop == "" {
+			continue
+		}
+		if i := strings.Index(text, stop); i >= 0 && (cut < 0 || i < cut) {
+			cut = i
+		}
+	}
+	if cut < 0 {
+		return text, false
+	}
+	return text[:cut], true
+}
+
+// openAIFinishReason maps provider finish reasons onto the OpenAI vocabulary
+func openAIFinishReason(reason string, stopped bool) string {
+	if stopped {
+		return "stop"
+	}
+	switch reason {
+	case "length", "max_tokens":
+		return "length"
+	default:
+		return "stop"
+	}
+}
+
+// openAIErrorBody converts an error response into the OpenAI wire format
+func openAIErrorBody(errResp *model.ErrorResponse) *model.OpenAIErrorResponse {
+	errType := errResp.Type
+	switch errResp.Type {
+	case "validation_error":
+		errType = "invalid_request_error"
+	case "service_error":
+		errType = "server_error"
+	}
+	return &model.OpenAIErrorResponse{
+		Error: model.OpenAIError{
+			Message: errResp.Message,
+			Type:    errType,
+			Code:    errResp.Code,
+		},
+	}
+}
+
+// handleOpenAIError writes an error response in the OpenAI wire format
+func (h *AIHandler) handleOpenAIError(ctx context.Context, w http.ResponseWriter, errResp *model.ErrorResponse) {
+	h.logger.Error(ctx, "Request error", map[string]interface{}{
+		"error_code":    errResp.Code,
+		"error_message": errResp.Message,
+		"error_type":    errResp.Type,
+	})
+
+	h.sendJSONResponse(ctx, w, errResp.Code, openAIErrorBody(errResp))
+}
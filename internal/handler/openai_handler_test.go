@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/cache"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/tokenizer"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func TestChatCompletionsStreamMultiByte(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		want   string
+	}{
+		{name: "accented characters", tokens: []string{"Caf", "é", " olé", "\nuser:", " more"}, want: "Café olé"},
+		{name: "CJK characters", tokens: []string{"日本", "語", "\nuse", "r:"}, want: "日本語"},
+		{name: "character split across tokens", tokens: []string{"Caf", "\xc3", "\xa9 ", "日\xe6\x9c", "\xac", "\nuser:"}, want: "Café 日本"},
+		{name: "no stop sequence", tokens: []string{"ü", "ber"}, want: "über"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockAIService()
+			mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+				for i, token := range tt.tokens {
+					if err := fn(&model.StreamChunk{ID: req.ID, Model: req.Model, Index: i, Text: token}); err != nil {
+						return nil, err
+					}
+				}
+				return &model.AIResponse{ID: req.ID, Model: req.Model, Choices: []model.Choice{{FinishReason: "stop"}}}, nil
+			}
+			h := NewAIHandler(mock, metrics.New(), logger.NewNoopLogger())
+
+			body := `{"model": "gpt2", "stream": true, "messages": [{"role": "user", "content": "Hi"}]}`
+			rec := httptest.NewRecorder()
+			h.ChatCompletions(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+
+			var content strings.Builder
+			scanner := bufio.NewScanner(rec.Body)
+			for scanner.Scan() {
+				data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+				if !ok || data == "[DONE]" {
+					continue
+				}
+				var chunk model.OpenAIChatCompletionChunk
+				if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+					t.Fatalf("invalid chunk %q: %v", data, err)
+				}
+				for _, choice := range chunk.Choices {
+					delta := choice.Delta.Content
+					if !utf8.ValidString(delta) || strings.ContainsRune(delta, utf8.RuneError) {
+						t.Errorf("chunk content %q is not valid UTF-8", delta)
+					}
+					content.WriteString(delta)
+				}
+			}
+
+			if got := content.String(); got != tt.want {
+				t.Errorf("streamed content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuneBoundary(t *testing.T) {
+	text := "aé日" // a, é (2 bytes), 日 (3 bytes)
+	tests := []struct {
+		i    int
+		want int
+	}{
+		{i: 0, want: 0},
+		{i: 1, want: 1},
+		{i: 2, want: 1},
+		{i: 3, want: 3},
+		{i: 4, want: 3},
+		{i: 5, want: 3},
+		{i: 6, want: 6},
+		{i: -2, want: -2},
+	}
+
+	for _, tt := range tests {
+		if got := runeBoundary(text, tt.i); got != tt.want {
+			t.Errorf("runeBoundary(%q, %d) = %d, want %d", text, tt.i, got, tt.want)
+		}
+	}
+
+	if got := runeBoundary("ab\xe6\x9c", 4); got != 2 {
+		t.Errorf("runeBoundary() with a partial trailing character = %d, want 2", got)
+	}
+}
+
+func TestCompletionsTemperature(t *testing.T) {
+	tests := []struct {
+		name            string
+		temperature     string
+		wantCode        int
+		wantTemperature float32
+		wantDistinct    bool
+	}{
+		{name: "omitted temperature", wantCode: http.StatusOK, wantTemperature: 0.7, wantDistinct: true},
+		{name: "zero temperature", temperature: `, "temperature": 0`, wantCode: http.StatusOK},
+		{name: "temperature above 1", temperature: `, "temperature": 1.5`, wantCode: http.StatusOK, wantTemperature: 1.5, wantDistinct: true},
+		{name: "temperature above 2", temperature: `, "temperature": 2.5`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockAIService()
+			var temperatures []float32
+			mock.GenerateTextFunc = func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+				temperatures = append(temperatures, req.Temperature)
+				text := fmt.Sprintf("sample %d", len(temperatures))
+				return &model.AIResponse{ID: req.ID, Model: req.Model, Choices: []model.Choice{{Text: text, FinishReason: "stop"}}}, nil
+			}
+			// Wired as in the server: defaults above the response cache
+			var service model.AIService = ai.NewCachedService(mock, cache.NewLRU(16), time.Minute, metrics.New(), logger.NewNoopLogger())
+			service = ai.NewDefaultsService(service, &config.ModelsConfig{}, &config.HuggingFaceConfig{MaxTokens: 100, Temperature: 0.7}, nil)
+			h := NewAIHandler(service, metrics.New(), logger.NewNoopLogger())
+
+			body := `{"model": "gpt2", "prompt": "Hello", "n": 2` + tt.temperature + `}`
+			rec := httptest.NewRecorder()
+			h.Completions(rec, httptest.NewRequest(http.MethodPost, "/v1/completions", strings.NewReader(body)))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Completions() status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp model.OpenAICompletionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if len(resp.Choices) != 2 {
+				t.Fatalf("Completions() returned %d choices, want 2", len(resp.Choices))
+			}
+			if distinct := resp.Choices[0].Text != resp.Choices[1].Text; distinct != tt.wantDistinct {
+				t.Errorf("Completions() choices %q and %q, want distinct %v", resp.Choices[0].Text, resp.Choices[1].Text, tt.wantDistinct)
+			}
+			if temperatures[0] != tt.wantTemperature {
+				t.Errorf("Completions() sent temperature %v, want %v", temperatures[0], tt.wantTemperature)
+			}
+		})
+	}
+}
+
+func TestOpenAIFallbackModel(t *testing.T) {
+	mock := mocks.NewMockAIService()
+	generate := mock.GenerateTextFunc
+	mock.GenerateTextFunc = func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error) {
+		if req.Model == "gpt2-large" {
+			return nil, &ai.UpstreamError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return generate(ctx, req)
+	}
+	mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+		if req.Model == "gpt2-large" {
+			return nil, &ai.UpstreamError{StatusCode: http.StatusServiceUnavailable}
+		}
+		if err := fn(&model.StreamChunk{ID: req.ID, Model: req.Model, Text: "Hello"}); err != nil {
+			return nil, err
+		}
+		return &model.AIResponse{ID: req.ID, Model: req.Model, Choices: []model.Choice{{FinishReason: "stop"}}}, nil
+	}
+	service := ai.NewFallbackService(mock, &config.FallbackConfig{
+		Models: map[string]string{"gpt2-large": "gpt2"},
+	}, &config.HuggingFaceConfig{}, metrics.New(), logger.NewNoopLogger())
+	h := NewAIHandler(service, metrics.New(), logger.NewNoopLogger())
+
+	for _, stream := range []bool{false, true} {
+		body := fmt.Sprintf(`{"model": "gpt2-large", "stream": %v, "messages": [{"role": "user", "content": "Hi"}]}`, stream)
+		rec := httptest.NewRecorder()
+		h.ChatCompletions(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("ChatCompletions() status = %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var models []string
+		if !stream {
+			var resp model.OpenAIChatCompletionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			models = append(models, resp.Model)
+		}
+		scanner := bufio.NewScanner(rec.Body)
+		for stream && scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok || data == "[DONE]" {
+				continue
+			}
+			var chunk model.OpenAIChatCompletionChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				t.Fatalf("invalid chunk %q: %v", data, err)
+			}
+			models = append(models, chunk.Model)
+		}
+
+		if len(models) == 0 {
+			t.Fatalf("stream %v: no response", stream)
+		}
+		for _, got := range models {
+			if got != "gpt2" {
+				t.Errorf("stream %v: model = %q, want the fallback model %q", stream, got, "gpt2")
+			}
+		}
+	}
+}
+
+func TestCompletionsStreamPromptTokens(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "org", "bert"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	bert := `{
+		"normalizer": {"type": "BertNormalizer", "lowercase": true},
+		"pre_tokenizer": {"type": "BertPreTokenizer"},
+		"post_processor": {"type": "BertProcessing", "sep": ["[SEP]", 2], "cls": ["[CLS]", 1]},
+		"model": {"type": "WordPiece", "unk_token": "[UNK]", "continuing_subword_prefix": "##",
+			"vocab": {"[UNK]": 0, "[CLS]": 1, "[SEP]": 2, "un": 3, "##aff": 4, "##able": 5, "hello": 6, "!": 7}}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "org", "bert", "tokenizer.json"), []byte(bert), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := mocks.NewMockAIService()
+	mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+		for _, token := range []string{"Hello", ".", " More"} {
+			if err := fn(&model.StreamChunk{ID: req.ID, Model: req.Model, Text: token}); err != nil {
+				return nil, err
+			}
+		}
+		return &model.AIResponse{ID: req.ID, Model: req.Model, Choices: []model.Choice{{FinishReason: "stop"}}}, nil
+	}
+	h := NewAIHandler(mock, metrics.New(), logger.NewNoopLogger(),
+		WithTokenizer(tokenizer.NewCounter(dir, logger.NewNoopLogger())))
+
+	// The stop sequence ends the stream before the service reports usage
+	body := `{"model": "org/bert", "prompt": "Unaffable hello!", "stream": true, "stop": ["."]}`
+	rec := httptest.NewRecorder()
+	h.Completions(rec, httptest.NewRequest(http.MethodPost, "/v1/completions", strings.NewReader(body)))
+
+	var usage *model.OpenAIUsage
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk model.OpenAICompletionResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("invalid chunk %q: %v", data, err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+
+	if usage == nil {
+		t.Fatalf("Completions() sent no usage: %s", rec.Body.String())
+	}
+	// [CLS] un ##aff ##able hello ! [SEP]
+	if usage.PromptTokens != 7 {
+		t.Errorf("usage prompt tokens = %d, want 7 from the model's tokenizer", usage.PromptTokens)
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// wantsStream reports whether the client asked for a Server-Sent Events
+// response, either through the request body or the Accept header
+func wantsStream(r *http.Request, req *model.AIRequest) bool {
+	if req.Stream {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// sseWriter writes Server-Sent Events and flushes them to the client
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// newSSEWriter prepares the response for Server-Sent Events
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	rc := http.NewResponseController(w)
+	// Streams outlive the server write timeout; the request context bounds them
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	return &sseWriter{w: w, rc: rc}
+}
+
+// send writes a single event and flushes it
+func (s *sseWriter) send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", payload); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// done writes the terminating event
+func (s *sseWriter) done() {
+	fmt.Fprint(s.w, "data: [DONE]\n\n")
+	s.rc.Flush()
+}
+
+// stopFilter holds streamed text back by the length of the longest stop
+// sequence, so that a stop sequence split across tokens is never sent to the
+// client, and cuts the text at the first stop sequence
+type stopFilter struct {
+	stops    []string
+	holdBack int
+	text     strings.Builder
+	sent     int
+	stopped  bool
+}
+
+// newStopFilter creates a filter for the given stop sequences
+func newStopFilter(stops []string) *stopFilter {
+	f := &stopFilter{stops: stops}
+	for _, stop := range stops {
+		if len(stop) > f.holdBack {
+			f.holdBack = len(stop)
+		}
+	}
+	return f
+}
+
+// write adds the text of a token and returns the text that is safe to send.
+// Once a stop sequence has been produced, stopped is set and nothing more is
+// returned.
+func (f *stopFilter) write(token string) string {
+	if f.stopped {
+		return ""
+	}
+	f.text.WriteString(token)
+
+	text, hit := applyStopSequences(f.text.String(), f.stops)
+	end := len(text)
+	if hit {
+		f.stopped = true
+	} else {
+		end = runeBoundary(text, len(text)-f.holdBack)
+	}
+	if end <= f.sent {
+		return ""
+	}
+	out := text[f.sent:end]
+	f.sent = end
+	return out
+}
+
+// special reports whether a special token is one of the stop sequences, in
+// which case the stream is stopped and the held-back text can be flushed
+func (f *stopFilter) special(token string) bool {
+	for _, stop := range f.stops {
+		if stop != "" && token == stop {
+			f.stopped = true
+			return true
+		}
+	}
+	return false
+}
+
+// flush returns the text still held back once the stream has ended
+func (f *stopFilter) flush() string {
+	out := f.String()[f.sent:]
+	f.sent += len(out)
+	return out
+}
+
+// String returns the text generated up to the first stop sequence
+func (f *stopFilter) String() string {
+	text, _ := applyStopSequences(f.text.String(), f.stops)
+	return text
+}
+
+// streamText proxies a streaming generation to the client as Server-Sent Events.
+// Tokens are sent as "token" events, followed by a "usage" event carrying the
+// complete response. Output is cut at the first of stops, which is never sent.
+// Failures after the stream has started are reported as an "error" event
+// since the status code has already been sent.
+func (h *AIHandler) streamText(ctx context.Context, w http.ResponseWriter, req *model.AIRequest, stops []string) {
+	h.logger.Info(ctx, "Streaming text generation", map[string]interface{}{
+		"request_id": req.ID,
+		"model":      req.Model,
+	})
+
+	var sse *sseWriter
+	filter := newStopFilter(stops)
+	servedBy := req.Model // the model reported by the service, which may be a fallback
+	index := 0
+	completionTokens := 0
+
+	send := func(chunk model.StreamChunk) error {
+		if sse == nil {
+			sse = newSSEWriter(w)
+		}
+		if chunk.Text == "" && !chunk.Special {
+			return nil
+		}
+		chunk.Index = index
+		index++
+		return sse.send("token", &chunk)
+	}
+
+	response, err := h.aiService.GenerateTextStream(ctx, req, func(chunk *model.StreamChunk) error {
+		if chunk.Model != "" {
+			servedBy = chunk.Model
+		}
+		if chunk.Special {
+			if filter.special(chunk.Text) {
+				return errStopSequence
+			}
+			return send(*chunk)
+		}
+
+		completionTokens++
+		text := filter.write(chunk.Text)
+		next := *chunk
+		next.Text = text
+		if err := send(next); err != nil {
+			return err
+		}
+		if filter.stopped {
+			return errStopSequence
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errStopSequence) {
+		if ctx.Err() != nil {
+			h.logger.Info(ctx, "Client disconnected during stream", map[string]interface{}{
+				"request_id": req.ID,
+			})
+			return
+		}
+
+		h.logger.Error(ctx, "Failed to stream text", map[string]interface{}{
+			"error": err.Error(),
+		})
+		errResp := h.serviceError(ctx, err, "Failed to generate text")
+		if sse == nil {
+			setRetryAfter(w, err)
+			h.handleError(ctx, w, errResp)
+			return
+		}
+		sse.send("error", errResp)
+		sse.done()
+		return
+	}
+
+	// Flush any held-back text
+	if err := send(model.StreamChunk{ID: req.ID, Model: servedBy, Text: filter.flush()}); err != nil {
+		return
+	}
+
+	if response == nil {
+		// The stream was cut at a stop sequence before the service returned
+		promptTokens := h.tokens.CountInput(req.Model, req.Prompt)
+		response = &model.AIResponse{
+			ID:          req.ID,
+			Model:       servedBy,
+			GeneratedAt: time.Now(),
+			Choices:     []model.Choice{{Text: filter.String(), FinishReason: "stop"}},
+			Usage: model.Usage{
+				PromptTokens:     promptTokens,
+				CompletionTokens: completionTokens,
+				TotalTokens:      promptTokens + completionTokens,
+			},
+		}
+	} else if filter.stopped && len(response.Choices) > 0 {
+		response.Choices[0].Text = filter.String()
+		response.Choices[0].FinishReason = "stop"
+	}
+
+	h.reconcileUsage(ctx, response.Usage)
+	if err := sse.send("usage", response); err != nil {
+		return
+	}
+	sse.done()
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/metrics"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+// sseEvent is an event read back from a streamed response
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readSSE parses the events of a Server-Sent Events response
+func readSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var name string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if event, ok := strings.CutPrefix(line, "event: "); ok {
+			name = event
+			continue
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			events = append(events, sseEvent{name: name, data: data})
+			name = ""
+		}
+	}
+	return events
+}
+
+func TestStreamText(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		disconnect bool
+		wantEvents []string
+		wantText   string
+	}{
+		{name: "complete stream", wantEvents: []string{"token", "token", "usage", ""}, wantText: "Hello world"},
+		{name: "truncated stream", err: ai.ErrStreamTruncated, wantEvents: []string{"token", "token", "error", ""}, wantText: "Hello world"},
+		{name: "client disconnect", disconnect: true, wantEvents: []string{"token", "token"}, wantText: "Hello world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mock := mocks.NewMockAIService()
+			mock.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+				for i, token := range []string{"Hello", " world"} {
+					if err := fn(&model.StreamChunk{ID: req.ID, Model: req.Model, Index: i, Text: token}); err != nil {
+						return nil, err
+					}
+				}
+				if tt.disconnect {
+					cancel()
+					return nil, ctx.Err()
+				}
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &model.AIResponse{
+					ID:      req.ID,
+					Model:   req.Model,
+					Choices: []model.Choice{{Text: "Hello world", FinishReason: "stop"}},
+					Usage:   model.Usage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+				}, nil
+			}
+			h := NewAIHandler(mock, metrics.New(), logger.NewNoopLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/text/generate", strings.NewReader(`{"model": "gpt2", "prompt": "Hi", "stream": true}`))
+			rec := httptest.NewRecorder()
+			h.GenerateText(rec, req.WithContext(ctx))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GenerateText() status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Content-Type = %q, want text/event-stream", got)
+			}
+
+			events := readSSE(t, rec.Body.String())
+			var names []string
+			var text strings.Builder
+			for _, event := range events {
+				names = append(names, event.name)
+				switch event.name {
+				case "token":
+					var chunk model.StreamChunk
+					if err := json.Unmarshal([]byte(event.data), &chunk); err != nil {
+						t.Fatalf("invalid token event %q: %v", event.data, err)
+					}
+					text.WriteString(chunk.Text)
+				case "usage":
+					var response model.AIResponse
+					if err := json.Unmarshal([]byte(event.data), &response); err != nil {
+						t.Fatalf("invalid usage event %q: %v", event.data, err)
+					}
+					if response.Usage.TotalTokens != 3 {
+						t.Errorf("usage total tokens = %d, want 3", response.Usage.TotalTokens)
+					}
+				case "error":
+					var errResp model.ErrorResponse
+					if err := json.Unmarshal([]byte(event.data), &errResp); err != nil {
+						t.Fatalf("invalid error event %q: %v", event.data, err)
+					}
+				case "":
+					if event.data != "[DONE]" {
+						t.Errorf("unnamed event %q, want [DONE]", event.data)
+					}
+				}
+			}
+
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantEvents) {
+				t.Errorf("GenerateText() sent events %q, want %q", names, tt.wantEvents)
+			}
+			if text.String() != tt.wantText {
+				t.Errorf("GenerateText() streamed %q, want %q", text.String(), tt.wantText)
+			}
+		})
+	}
+}
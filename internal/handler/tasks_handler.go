@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// ClassifyText handles zero-shot classification requests
+func (h *AIHandler) ClassifyText(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received classification request", nil)
+
+	var req model.ClassificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	response, err := h.aiService.ClassifyZeroShot(ctx, &req)
+	if err != nil {
+		h.handleServiceError(ctx, w, err, "Failed to classify text")
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ExtractEntities handles named entity recognition requests
+func (h *AIHandler) ExtractEntities(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received entity extraction request", nil)
+
+	var req model.EntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	response, err := h.aiService.ExtractEntities(ctx, &req)
+	if err != nil {
+		h.handleServiceError(ctx, w, err, "Failed to extract entities")
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// TranslateText handles translation requests
+func (h *AIHandler) TranslateText(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received translation request", nil)
+
+	var req model.TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	if errResp := checkModelAccess(ctx, req.Model); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	response, err := h.aiService.Translate(ctx, &req)
+	if err != nil {
+		h.handleServiceError(ctx, w, err, "Failed to translate text")
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// AnswerQuestion handles extractive question answering requests
+func (h *AIHandler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := h.setRequestID(r.Context())
+	h.logger.Info(ctx, "Received question answering request", nil)
+
+	var req model.QARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handleError(ctx, w, &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid JSON: %v", err),
+			Type:    "validation_error",
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		if errResp, ok := err.(*model.ErrorResponse); ok {
+			h.handleError(ctx, w, errResp)
+		} else {
+			h.handleError(ctx, w, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Type:    "validation_error",
+			})
+		}
+		return
+	}
+
+	// Questions are always answered by the configured model
+	var qaModel string
+	if h.tasks != nil {
+		qaModel = h.tasks.QAModel
+	}
+	if errResp := checkModelAccess(ctx, qaModel); errResp != nil {
+		h.handleError(ctx, w, errResp)
+		return
+	}
+
+	response, err := h.aiService.AnswerQuestion(ctx, req.Question, req.Context)
+	if err != nil {
+		h.handleServiceError(ctx, w, err, "Failed to answer question")
+		return
+	}
+
+	h.sendJSONResponse(ctx, w, http.StatusOK, response)
+}
@@ -0,0 +1,433 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tusharr/go-ai-huggingface/internal/ai"
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/internal/ratelimit"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// Errors returned by Manager.Submit
+var (
+	ErrQueueFull = errors.New("job queue is full")
+	ErrClosed    = errors.New("job manager is shutting down")
+)
+
+// defaultSummaryLength matches the synchronous summarize endpoint
+const defaultSummaryLength = 130
+
+// Manager runs jobs on a fixed pool of workers and delivers results to
+// callback URLs. Callbacks are delivered apart from the workers, so a slow or
+// unreachable callback URL does not hold up the queue.
+type Manager struct {
+	service    model.AIService
+	store      Store
+	config     *config.JobsConfig
+	httpClient *http.Client
+	logger     logger.Logger
+
+	mu        sync.RWMutex
+	closed    bool
+	queue     chan *task
+	wg        sync.WaitGroup
+	callbacks sync.WaitGroup  // deliveries in progress
+	ctx       context.Context // cancelled to abandon pending deliveries
+	stop      context.CancelFunc
+}
+
+// task is a queued job together with its decoded operation
+type task struct {
+	job          *model.Job
+	run          operation
+	reservations ratelimit.Reservations
+}
+
+// operation executes a job and returns its result and token usage
+type operation func(ctx context.Context) (interface{}, model.Usage, error)
+
+// NewManager creates a manager and starts its workers
+func NewManager(service model.AIService, store Store, cfg *config.JobsConfig, logger logger.Logger) *Manager {
+	m := &Manager{
+		service:    service,
+		store:      store,
+		config:     cfg,
+		httpClient: newCallbackClient(cfg),
+		logger:     logger,
+		queue:      make(chan *task, cfg.QueueSize),
+	}
+	m.ctx, m.stop = context.WithCancel(context.Background())
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	return m
+}
+
+// Submit validates a job request and queues it. The job is owned by the
+// tenant authenticated in ctx, and token rate limit reservations in ctx are
+// reconciled once the job has run.
+func (m *Manager) Submit(ctx context.Context, req *model.JobRequest) (*model.Job, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	run, err := m.operation(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.CallbackURL != "" && !m.config.CallbackAllowPrivate {
+		if err := checkCallbackURL(req.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	job := &model.Job{
+		ID:          uuid.New().String(),
+		Type:        req.Type,
+		Status:      model.JobStatusQueued,
+		Input:       req.Input,
+		CallbackURL: req.CallbackURL,
+		CreatedAt:   time.Now(),
+	}
+	if tenant := auth.TenantFromContext(ctx); tenant != nil {
+		job.TenantID = tenant.ID
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	if err := m.store.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+	// The worker owns job from here on
+	queued := *job
+
+	select {
+	case m.queue <- &task{job: job, run: run, reservations: ratelimit.ReservationsFromContext(ctx)}:
+	default:
+		m.store.Delete(ctx, job.ID)
+		return nil, ErrQueueFull
+	}
+
+	m.logger.Info(ctx, "Job queued", map[string]interface{}{
+		"job_id": job.ID,
+		"type":   job.Type,
+	})
+	return &queued, nil
+}
+
+// Get returns a job by ID
+func (m *Manager) Get(ctx context.Context, id string) (*model.Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Shutdown stops accepting jobs and waits for queued and running jobs and
+// their callbacks to finish or for ctx to expire, which abandons the
+// callbacks still pending
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		// Workers start deliveries, so none start once they have exited
+		m.callbacks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		m.stop()
+		return ctx.Err()
+	}
+}
+
+// operation decodes and validates the job input
+func (m *Manager) operation(req *model.JobRequest) (operation, error) {
+	switch req.Type {
+	case model.JobTypeGenerate:
+		var aiReq model.AIRequest
+		if err := decodeInput(req.Input, &aiReq); err != nil {
+			return nil, err
+		}
+		if aiReq.ID == "" {
+			aiReq.ID = uuid.New().String()
+		}
+		aiReq.CreatedAt = time.Now()
+		aiReq.Stream = false
+		if err := aiReq.Validate(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (interface{}, model.Usage, error) {
+			response, err := m.service.GenerateText(ctx, &aiReq)
+			if err != nil {
+				return nil, model.Usage{}, err
+			}
+			return response, response.Usage, nil
+		}, nil
+
+	case model.JobTypeSentiment, model.JobTypeSummarize:
+		var input model.TextInput
+		if err := decodeInput(req.Input, &input); err != nil {
+			return nil, err
+		}
+		if input.Text == "" {
+			return nil, &model.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "input.text is required",
+				Type:    "validation_error",
+			}
+		}
+		if req.Type == model.JobTypeSentiment {
+			return func(ctx context.Context) (interface{}, model.Usage, error) {
+				response, err := m.service.AnalyzeSentiment(ctx, &model.SentimentRequest{
+					Model: input.Model,
+					Text:  input.Text,
+				})
+				return response, model.Usage{}, err
+			}, nil
+		}
+		if input.MaxLength <= 0 {
+			input.MaxLength = defaultSummaryLength
+		}
+		return func(ctx context.Context) (interface{}, model.Usage, error) {
+			response, err := m.service.SummarizeText(ctx, &model.SummaryRequest{
+				Model:     input.Model,
+				Text:      input.Text,
+				MaxLength: input.MaxLength,
+				Strategy:  input.Strategy,
+			})
+			return response, model.Usage{}, err
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported job type %q", req.Type)
+}
+
+func decodeInput(input json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(input, v); err != nil {
+		return &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid input: %v", err),
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for t := range m.queue {
+		m.execute(t)
+	}
+}
+
+// execute runs a job, records its outcome and delivers the callback
+func (m *Manager) execute(t *task) {
+	job := t.job
+	ctx := context.WithValue(context.Background(), "request_id", job.ID)
+
+	started := time.Now()
+	job.Status = model.JobStatusRunning
+	job.StartedAt = &started
+	m.save(ctx, job)
+
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if m.config.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, m.config.Timeout)
+	}
+	result, usage, err := t.run(runCtx)
+	cancel()
+
+	completed := time.Now()
+	job.CompletedAt = &completed
+	if err == nil {
+		job.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		job.Status = model.JobStatusFailed
+		// Failed jobs are stored and served later, so the error text only
+		// goes to the log below
+		job.Error = ai.ErrorResponse(err, fmt.Sprintf("Failed to run %s job", job.Type), false)
+		m.logger.Error(ctx, "Job failed", map[string]interface{}{
+			"job_id": job.ID,
+			"error":  err.Error(),
+		})
+	} else {
+		job.Status = model.JobStatusSucceeded
+		m.logger.Info(ctx, "Job succeeded", map[string]interface{}{
+			"job_id":      job.ID,
+			"duration_ms": completed.Sub(started).Milliseconds(),
+		})
+	}
+	t.reservations.Reconcile(usage.TotalTokens)
+	m.save(ctx, job)
+
+	if job.CallbackURL != "" {
+		m.callbacks.Add(1)
+		go func() {
+			defer m.callbacks.Done()
+			m.deliver(context.WithValue(m.ctx, "request_id", job.ID), job)
+		}()
+	}
+}
+
+func (m *Manager) save(ctx context.Context, job *model.Job) {
+	if err := m.store.Save(ctx, job); err != nil {
+		m.logger.Error(ctx, "Failed to save job", map[string]interface{}{
+			"job_id": job.ID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// deliver posts the finished job to its callback URL, retrying failed
+// deliveries with a linear backoff until ctx is cancelled
+func (m *Manager) deliver(ctx context.Context, job *model.Job) {
+	body, err := json.Marshal(job)
+	if err != nil {
+		m.logger.Error(ctx, "Failed to encode job callback", map[string]interface{}{
+			"job_id": job.ID,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	attempts := m.config.CallbackRetries + 1
+	attempt := 1
+	for ; ; attempt++ {
+		err = m.postCallback(ctx, job, body)
+		if err == nil {
+			m.logger.Info(ctx, "Job callback delivered", map[string]interface{}{
+				"job_id":  job.ID,
+				"attempt": attempt,
+			})
+			return
+		}
+		if attempt == attempts || !sleep(ctx, time.Duration(attempt)*time.Second) {
+			break
+		}
+	}
+
+	m.logger.Error(ctx, "Failed to deliver job callback", map[string]interface{}{
+		"job_id":   job.ID,
+		"attempts": attempt,
+		"error":    err.Error(),
+	})
+}
+
+func (m *Manager) postCallback(ctx context.Context, job *model.Job, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Job-ID", job.ID)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sleep waits for d and reports whether it did before ctx was cancelled
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// newCallbackClient returns the HTTP client callbacks are posted with. Unless
+// private callbacks are allowed, it refuses to connect to non-public
+// addresses; the check runs on the resolved address, so it also covers host
+// names and redirects. Callbacks bypass proxies for the check to see the
+// callback host.
+func newCallbackClient(cfg *config.JobsConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.CallbackAllowPrivate {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   checkCallbackAddress,
+		}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	}
+	return &http.Client{Timeout: cfg.CallbackTimeout, Transport: transport}
+}
+
+// checkCallbackAddress is a net.Dialer Control function refusing connections
+// to non-public addresses
+func checkCallbackAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("callback address %s is not public", host)
+	}
+	return nil
+}
+
+// checkCallbackURL rejects callback URLs naming a non-public address
+// directly. Host names are checked when the callback connects.
+func checkCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && !publicIP(ip)) {
+		return &model.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "callback_url must not point to a loopback, link-local or private address",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip is a globally routable unicast address
+func publicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
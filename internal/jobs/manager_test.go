@@ -0,0 +1,287 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/auth"
+	"github.com/tusharr/go-ai-huggingface/internal/config"
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+	"github.com/tusharr/go-ai-huggingface/test/mocks"
+)
+
+func newTestManager(t *testing.T, service model.AIService, cfg config.JobsConfig) *Manager {
+	t.Helper()
+	m := NewManager(service, NewMemoryStore(time.Hour), &cfg, logger.NewLogger(logger.ErrorLevel, false))
+	t.Cleanup(func() { m.Shutdown(context.Background()) })
+	return m
+}
+
+func waitForJob(t *testing.T, m *Manager, id string) *model.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get() unexpected error = %v", err)
+		}
+		if job.Done() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestManagerRunsJobs(t *testing.T) {
+	service := mocks.NewMockAIService()
+	service.SetSummarizeTextError(errors.New("model overloaded"))
+	m := newTestManager(t, service, config.JobsConfig{Workers: 2, QueueSize: 10})
+
+	tests := []struct {
+		name       string
+		request    model.JobRequest
+		wantStatus string
+	}{
+		{
+			name:       "generate",
+			request:    model.JobRequest{Type: model.JobTypeGenerate, Input: json.RawMessage(`{"model": "gpt2", "prompt": "Hello"}`)},
+			wantStatus: model.JobStatusSucceeded,
+		},
+		{
+			name:       "sentiment",
+			request:    model.JobRequest{Type: model.JobTypeSentiment, Input: json.RawMessage(`{"text": "I love it"}`)},
+			wantStatus: model.JobStatusSucceeded,
+		},
+		{
+			name:       "failing summarize",
+			request:    model.JobRequest{Type: model.JobTypeSummarize, Input: json.RawMessage(`{"text": "Long text"}`)},
+			wantStatus: model.JobStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := m.Submit(context.Background(), &tt.request)
+			if err != nil {
+				t.Fatalf("Submit() unexpected error = %v", err)
+			}
+			if job.Status != model.JobStatusQueued {
+				t.Errorf("Submit() status = %v, want %v", job.Status, model.JobStatusQueued)
+			}
+
+			done := waitForJob(t, m, job.ID)
+			if done.Status != tt.wantStatus {
+				t.Fatalf("job status = %v, want %v", done.Status, tt.wantStatus)
+			}
+			if done.StartedAt == nil || done.CompletedAt == nil {
+				t.Errorf("job timestamps not set: %+v", done)
+			}
+			if tt.wantStatus == model.JobStatusSucceeded && len(done.Result) == 0 {
+				t.Errorf("succeeded job has no result")
+			}
+			// The error text is logged but not stored with the job
+			if tt.wantStatus == model.JobStatusFailed && (done.Error == nil || done.Error.Type != "service_error" || done.Error.Details != nil) {
+				t.Errorf("failed job error = %+v", done.Error)
+			}
+		})
+	}
+}
+
+func TestManagerSubmitValidation(t *testing.T) {
+	m := newTestManager(t, mocks.NewMockAIService(), config.JobsConfig{Workers: 1, QueueSize: 10})
+
+	tests := []struct {
+		name    string
+		request model.JobRequest
+		errMsg  string
+	}{
+		{
+			name:    "unknown type",
+			request: model.JobRequest{Type: "translate", Input: json.RawMessage(`{}`)},
+			errMsg:  "type must be one of generate, sentiment, summarize",
+		},
+		{
+			name:    "invalid generate input",
+			request: model.JobRequest{Type: model.JobTypeGenerate, Input: json.RawMessage(`{"model": "gpt2"}`)},
+			errMsg:  "prompt is required",
+		},
+		{
+			name:    "missing text",
+			request: model.JobRequest{Type: model.JobTypeSummarize, Input: json.RawMessage(`{"max_length": 50}`)},
+			errMsg:  "input.text is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Submit(context.Background(), &tt.request)
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("Submit() error = %v, want %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestManagerQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	service := mocks.NewMockAIService()
+	service.AnalyzeSentimentFunc = func(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
+		<-release
+		return &model.SentimentResponse{Text: req.Text}, nil
+	}
+	m := newTestManager(t, service, config.JobsConfig{Workers: 1, QueueSize: 1})
+	defer close(release)
+
+	req := &model.JobRequest{Type: model.JobTypeSentiment, Input: json.RawMessage(`{"text": "x"}`)}
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		_, err = m.Submit(context.Background(), req)
+	}
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit() error = %v, want %v", err, ErrQueueFull)
+	}
+}
+
+func TestManagerCallback(t *testing.T) {
+	received := make(chan *model.Job, 1)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var job model.Job
+		json.Unmarshal(body, &job)
+		received <- &job
+	}))
+	defer server.Close()
+
+	m := newTestManager(t, mocks.NewMockAIService(), config.JobsConfig{
+		Workers: 1, QueueSize: 1, CallbackTimeout: time.Second, CallbackRetries: 1, CallbackAllowPrivate: true,
+	})
+
+	ctx := auth.WithTenant(context.Background(), &auth.Tenant{ID: "search"})
+	job, err := m.Submit(ctx, &model.JobRequest{
+		Type:        model.JobTypeSentiment,
+		Input:       json.RawMessage(`{"text": "great"}`),
+		CallbackURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Submit() unexpected error = %v", err)
+	}
+	if job.TenantID != "search" {
+		t.Errorf("Submit() tenant = %v, want search", job.TenantID)
+	}
+
+	select {
+	case delivered := <-received:
+		if delivered.ID != job.ID || delivered.Status != model.JobStatusSucceeded {
+			t.Errorf("callback job = %+v, want succeeded job %s", delivered, job.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
+
+func TestManagerCallbackOutsideWorkers(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the client going away once the body is read
+		io.ReadAll(r.Body)
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer server.Close()
+
+	m := NewManager(mocks.NewMockAIService(), NewMemoryStore(time.Hour), &config.JobsConfig{
+		Workers: 1, QueueSize: 10, CallbackTimeout: time.Minute, CallbackAllowPrivate: true,
+	}, logger.NewLogger(logger.ErrorLevel, false))
+
+	input := json.RawMessage(`{"text": "great"}`)
+	if _, err := m.Submit(context.Background(), &model.JobRequest{Type: model.JobTypeSentiment, Input: input, CallbackURL: server.URL}); err != nil {
+		t.Fatalf("Submit() unexpected error = %v", err)
+	}
+	// The only worker moves on while the callback hangs
+	next, err := m.Submit(context.Background(), &model.JobRequest{Type: model.JobTypeSentiment, Input: input})
+	if err != nil {
+		t.Fatalf("Submit() unexpected error = %v", err)
+	}
+	waitForJob(t, m, next.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending callback was not abandoned on shutdown")
+	}
+}
+
+func TestManagerCallbackPrivateAddress(t *testing.T) {
+	m := newTestManager(t, mocks.NewMockAIService(), config.JobsConfig{Workers: 1, QueueSize: 10})
+	_, err := m.Submit(context.Background(), &model.JobRequest{
+		Type:        model.JobTypeSentiment,
+		Input:       json.RawMessage(`{"text": "great"}`),
+		CallbackURL: "http://169.254.169.254/latest/meta-data",
+	})
+	if want := "callback_url must not point to a loopback, link-local or private address"; err == nil || err.Error() != want {
+		t.Errorf("Submit() error = %v, want %v", err, want)
+	}
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "http://127.0.0.1:8080/hook", wantErr: true},
+		{url: "http://localhost/hook", wantErr: true},
+		{url: "http://[::1]/hook", wantErr: true},
+		{url: "http://10.1.2.3/hook", wantErr: true},
+		{url: "http://0.0.0.0/hook", wantErr: true},
+		{url: "https://93.184.215.14/hook", wantErr: false},
+		{url: "https://example.com/hook", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := checkCallbackURL(tt.url); (err != nil) != tt.wantErr {
+				t.Errorf("checkCallbackURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Host names are checked once resolved
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("callback reached a loopback address")
+	}))
+	defer server.Close()
+	client := newCallbackClient(&config.JobsConfig{CallbackTimeout: time.Second})
+	if _, err := client.Post(server.URL, "application/json", nil); err == nil {
+		t.Error("callback client connected to a loopback address")
+	}
+}
+
+func TestManagerShutdown(t *testing.T) {
+	m := newTestManager(t, mocks.NewMockAIService(), config.JobsConfig{Workers: 1, QueueSize: 1})
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error = %v", err)
+	}
+
+	_, err := m.Submit(context.Background(), &model.JobRequest{Type: model.JobTypeSentiment, Input: json.RawMessage(`{"text": "x"}`)})
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit() after Shutdown error = %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+// ErrNotFound is returned by a Store for unknown or expired jobs
+var ErrNotFound = errors.New("job not found")
+
+// Store persists jobs. Implementations must be safe for concurrent use and
+// must not retain or hand out references to the jobs they are given.
+type Store interface {
+	// Save creates or replaces a job
+	Save(ctx context.Context, job *model.Job) error
+	// Get returns a job by ID, or ErrNotFound
+	Get(ctx context.Context, id string) (*model.Job, error)
+	// Delete removes a job
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore keeps jobs in memory. Finished jobs are discarded once they
+// are older than the retention period.
+type MemoryStore struct {
+	mu        sync.Mutex
+	jobs      map[string]*model.Job
+	retention time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an in-memory store keeping finished jobs for retention
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		jobs:      make(map[string]*model.Job),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Save implements Store
+func (s *MemoryStore) Save(ctx context.Context, job *model.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	stored := *job
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, id string) (*model.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || s.expired(job) {
+		return nil, ErrNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryStore) expired(job *model.Job) bool {
+	return job.CompletedAt != nil && s.now().Sub(*job.CompletedAt) > s.retention
+}
+
+// sweep discards expired jobs at most once per retention period
+func (s *MemoryStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < s.retention {
+		return
+	}
+	s.lastSweep = now
+	for id, job := range s.jobs {
+		if s.expired(job) {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Hour)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	job := &model.Job{ID: "job-1", Status: model.JobStatusQueued}
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+
+	// The store keeps its own copy
+	job.Status = model.JobStatusRunning
+	got, err := store.Get(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if got.Status != model.JobStatusQueued {
+		t.Errorf("Get() status = %v, want %v", got.Status, model.JobStatusQueued)
+	}
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+
+	// Finished jobs expire after the retention period
+	completed := now
+	job.Status = model.JobStatusSucceeded
+	job.CompletedAt = &completed
+	store.Save(ctx, job)
+
+	now = now.Add(30 * time.Minute)
+	if _, err := store.Get(ctx, "job-1"); err != nil {
+		t.Errorf("Get() within retention unexpected error = %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := store.Get(ctx, "job-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after retention error = %v, want %v", err, ErrNotFound)
+	}
+
+	store.Save(ctx, &model.Job{ID: "job-2"})
+	if _, ok := store.jobs["job-1"]; ok {
+		t.Errorf("expired job was not swept")
+	}
+
+	store.Delete(ctx, "job-2")
+	if _, err := store.Get(ctx, "job-2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want %v", err, ErrNotFound)
+	}
+}
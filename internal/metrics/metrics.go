@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"io"
+	"strconv"
+	"time"
+)
+
+// Metrics holds the application metrics exposed on /metrics. All methods are
+// safe to call on a nil *Metrics, which makes instrumentation optional.
+type Metrics struct {
+	registry *Registry
+
+	httpRequests        *CounterVec
+	httpRequestDuration *HistogramVec
+	httpInFlight        *GaugeVec
+	upstreamRequests    *CounterVec
+	upstreamDuration    *HistogramVec
+	upstreamRetries     *CounterVec
+	breakerState        *GaugeVec
+	breakerRejections   *CounterVec
+	fallbacks           *CounterVec
+	coalesced           *CounterVec
+	rateLimitRejections *CounterVec
+	tokens              *CounterVec
+	cacheLookups        *CounterVec
+
+	knownModel func(model string) bool // models named in labels; nil for every model
+}
+
+// New creates the application metrics on a fresh registry
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		registry: r,
+		httpRequests: r.NewCounterVec("http_requests_total",
+			"Total number of HTTP requests by route, method and status code.",
+			"route", "method", "status"),
+		httpRequestDuration: r.NewHistogramVec("http_request_duration_seconds",
+			"HTTP request latency in seconds by route and method.",
+			DefaultBuckets, "route", "method"),
+		httpInFlight: r.NewGaugeVec("http_requests_in_flight",
+			"Number of HTTP requests currently being served."),
+		upstreamRequests: r.NewCounterVec("upstream_requests_total",
+			"Total number of requests sent to the inference API by model and status code.",
+			"model", "status"),
+		upstreamDuration: r.NewHistogramVec("upstream_request_duration_seconds",
+			"Inference API latency in seconds by model.",
+			[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60}, "model"),
+		upstreamRetries: r.NewCounterVec("upstream_retries_total",
+			"Total number of retried inference API requests by model.",
+			"model"),
+		breakerState: r.NewGaugeVec("circuit_breaker_state",
+			"Circuit breaker state by model (0 closed, 1 half-open, 2 open).",
+			"model"),
+		breakerRejections: r.NewCounterVec("circuit_breaker_rejections_total",
+			"Total number of requests rejected by an open circuit breaker by model.",
+			"model"),
+		fallbacks: r.NewCounterVec("model_fallbacks_total",
+			"Total number of requests handed to a fallback model by failed model and fallback model.",
+			"model", "fallback"),
+		coalesced: r.NewCounterVec("upstream_coalesced_total",
+			"Total number of inference requests served by an identical request already in flight by model.",
+			"model"),
+		rateLimitRejections: r.NewCounterVec("rate_limit_rejections_total",
+			"Total number of requests rejected by rate limiting.",
+			"limit"),
+		tokens: r.NewCounterVec("tokens_total",
+			"Total number of tokens processed by model and type (prompt or completion).",
+			"model", "type"),
+		cacheLookups: r.NewCounterVec("cache_lookups_total",
+			"Total number of response cache lookups by result (HIT, MISS or BYPASS).",
+			"result"),
+	}
+}
+
+// Registry returns the underlying registry so other components can register
+// their own metric families
+func (m *Metrics) Registry() *Registry {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+// LimitModels bounds the model label to the models known reports: every
+// other model is recorded as "other", so that clients naming arbitrary models
+// cannot create unbounded series. It must be called before the metrics are
+// used.
+func (m *Metrics) LimitModels(known func(model string) bool) {
+	if m == nil {
+		return
+	}
+	m.knownModel = known
+}
+
+// modelLabel returns the model label value of a model
+func (m *Metrics) modelLabel(model string) string {
+	if m.knownModel == nil || m.knownModel(model) {
+		return model
+	}
+	return "other"
+}
+
+// ObserveHTTPRequest records a completed HTTP request
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.Inc(route, method, strconv.Itoa(status))
+	m.httpRequestDuration.Observe(duration.Seconds(), route, method)
+}
+
+// TrackInFlight increments the in-flight gauge and returns a func that decrements it
+func (m *Metrics) TrackInFlight() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.httpInFlight.Add(1)
+	return func() { m.httpInFlight.Add(-1) }
+}
+
+// ObserveUpstream records a single request attempt to the inference API.
+// A status of 0 means the request failed before a response was received.
+func (m *Metrics) ObserveUpstream(model string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := "error"
+	if status > 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	model = m.modelLabel(model)
+	m.upstreamRequests.Inc(model, statusLabel)
+	m.upstreamDuration.Observe(duration.Seconds(), model)
+}
+
+// IncRetry records a retried inference API request
+func (m *Metrics) IncRetry(model string) {
+	if m == nil {
+		return
+	}
+	m.upstreamRetries.Inc(m.modelLabel(model))
+}
+
+// SetCircuitBreakerState records the state of a model's circuit breaker:
+// 0 closed, 1 half-open, 2 open
+func (m *Metrics) SetCircuitBreakerState(model string, state int) {
+	if m == nil {
+		return
+	}
+	m.breakerState.Set(float64(state), m.modelLabel(model))
+}
+
+// IncCircuitBreakerRejected records a request rejected by an open circuit
+// breaker
+func (m *Metrics) IncCircuitBreakerRejected(model string) {
+	if m == nil {
+		return
+	}
+	m.breakerRejections.Inc(m.modelLabel(model))
+}
+
+// IncFallback records a request handed from a failed model to a fallback
+// model
+func (m *Metrics) IncFallback(model, fallback string) {
+	if m == nil {
+		return
+	}
+	m.fallbacks.Inc(m.modelLabel(model), m.modelLabel(fallback))
+}
+
+// IncCoalesced records an inference request that shared the upstream call
+// of an identical request in flight
+func (m *Metrics) IncCoalesced(model string) {
+	if m == nil {
+		return
+	}
+	m.coalesced.Inc(m.modelLabel(model))
+}
+
+// IncRateLimited records a request rejected by the named limit
+func (m *Metrics) IncRateLimited(limit string) {
+	if m == nil {
+		return
+	}
+	m.rateLimitRejections.Inc(limit)
+}
+
+// AddTokens records token usage for a model
+func (m *Metrics) AddTokens(model string, promptTokens, completionTokens int) {
+	if m == nil {
+		return
+	}
+	model = m.modelLabel(model)
+	m.tokens.Add(float64(promptTokens), model, "prompt")
+	m.tokens.Add(float64(completionTokens), model, "completion")
+}
+
+// ObserveCache records a response cache lookup
+func (m *Metrics) ObserveCache(result string) {
+	if m == nil {
+		return
+	}
+	m.cacheLookups.Inc(result)
+}
+
+// Write renders all metrics in the Prometheus text exposition format
+func (m *Metrics) Write(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	return m.registry.Write(w)
+}
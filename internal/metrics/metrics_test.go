@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCounterVec(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_total", "Test counter.", "route")
+
+	c.Inc("/a")
+	c.Inc("/a")
+	c.Add(3, "/b")
+	c.Add(-1, "/b") // negative deltas are ignored
+
+	if got := c.Value("/a"); got != 2 {
+		t.Errorf("Value(/a) = %v, want %v", got, 2)
+	}
+	if got := c.Value("/b"); got != 3 {
+		t.Errorf("Value(/b) = %v, want %v", got, 3)
+	}
+}
+
+func TestGaugeVec(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeVec("test_gauge", "Test gauge.", "model")
+
+	g.Set(5, "gpt2")
+	g.Add(-2, "gpt2")
+
+	if got := g.Value("gpt2"); got != 3 {
+		t.Errorf("Value(gpt2) = %v, want %v", got, 3)
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("test_seconds", "Test histogram.", []float64{1, 0.1}, "model")
+
+	h.Observe(0.05, "gpt2")
+	h.Observe(0.5, "gpt2")
+	h.Observe(5, "gpt2")
+
+	if got := h.Count("gpt2"); got != 3 {
+		t.Errorf("Count(gpt2) = %v, want %v", got, 3)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+
+	want := []string{
+		"# TYPE test_seconds histogram",
+		`test_seconds_bucket{model="gpt2",le="0.1"} 1`,
+		`test_seconds_bucket{model="gpt2",le="1"} 2`,
+		`test_seconds_bucket{model="gpt2",le="+Inf"} 3`,
+		`test_seconds_sum{model="gpt2"} 5.55`,
+		`test_seconds_count{model="gpt2"} 3`,
+	}
+	for _, line := range want {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("output missing %q\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestRegistry_LabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_total", "Test counter.", "value")
+	c.Inc("a\"b\\c\nd")
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+
+	want := `test_total{value="a\"b\\c\nd"} 1`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q\n%s", want, buf.String())
+	}
+}
+
+func TestMetrics_Exposition(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTPRequest("/v1/text/generate", "POST", 200, 120*time.Millisecond)
+	m.ObserveUpstream("gpt2", 503, time.Second)
+	m.ObserveUpstream("gpt2", 0, time.Second)
+	m.IncRetry("gpt2")
+	m.SetCircuitBreakerState("gpt2", 2)
+	m.IncCircuitBreakerRejected("gpt2")
+	m.IncFallback("gpt2-large", "gpt2")
+	m.IncCoalesced("gpt2")
+	m.IncRateLimited("requests_per_minute")
+	m.AddTokens("gpt2", 10, 20)
+	done := m.TrackInFlight()
+	done()
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+	out := buf.String()
+
+	want := []string{
+		`http_requests_total{route="/v1/text/generate",method="POST",status="200"} 1`,
+		`http_request_duration_seconds_count{route="/v1/text/generate",method="POST"} 1`,
+		`http_requests_in_flight 0`,
+		`upstream_requests_total{model="gpt2",status="503"} 1`,
+		`upstream_requests_total{model="gpt2",status="error"} 1`,
+		`upstream_retries_total{model="gpt2"} 1`,
+		`circuit_breaker_state{model="gpt2"} 2`,
+		`circuit_breaker_rejections_total{model="gpt2"} 1`,
+		`model_fallbacks_total{model="gpt2-large",fallback="gpt2"} 1`,
+		`upstream_coalesced_total{model="gpt2"} 1`,
+		`rate_limit_rejections_total{limit="requests_per_minute"} 1`,
+		`tokens_total{model="gpt2",type="prompt"} 10`,
+		`tokens_total{model="gpt2",type="completion"} 20`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line) {
+			t.Errorf("output missing %q\n%s", line, out)
+		}
+	}
+}
+
+func TestMetrics_LimitModels(t *testing.T) {
+	m := New()
+	m.LimitModels(func(model string) bool { return model == "gpt2" || model == "gpt2-large" })
+
+	m.ObserveUpstream("gpt2", 200, time.Second)
+	m.ObserveUpstream("made-up-1", 404, time.Second)
+	m.ObserveUpstream("made-up-2", 404, time.Second)
+	m.IncRetry("made-up-1")
+	m.IncFallback("gpt2-large", "made-up-1")
+	m.IncCoalesced("made-up-2")
+	m.AddTokens("made-up-1", 10, 20)
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+	out := buf.String()
+
+	want := []string{
+		`upstream_requests_total{model="gpt2",status="200"} 1`,
+		`upstream_requests_total{model="other",status="404"} 2`,
+		`upstream_retries_total{model="other"} 1`,
+		`model_fallbacks_total{model="gpt2-large",fallback="other"} 1`,
+		`upstream_coalesced_total{model="other"} 1`,
+		`tokens_total{model="other",type="prompt"} 10`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line) {
+			t.Errorf("output missing %q\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "made-up") {
+		t.Errorf("output has an unregistered model label\n%s", out)
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+
+	// None of these should panic
+	m.ObserveHTTPRequest("/health", "GET", 200, time.Millisecond)
+	m.ObserveUpstream("gpt2", 200, time.Millisecond)
+	m.IncRetry("gpt2")
+	m.SetCircuitBreakerState("gpt2", 0)
+	m.IncCircuitBreakerRejected("gpt2")
+	m.IncFallback("gpt2-large", "gpt2")
+	m.IncRateLimited("requests_per_minute")
+	m.AddTokens("gpt2", 1, 1)
+	m.LimitModels(func(model string) bool { return true })
+	m.TrackInFlight()()
+
+	if err := m.Write(&bytes.Buffer{}); err != nil {
+		t.Errorf("Write() unexpected error = %v", err)
+	}
+}
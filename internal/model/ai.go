@@ -2,6 +2,8 @@ package model
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -14,7 +16,40 @@ type AIRequest struct {
 	Temperature float32           `json:"temperature,omitempty"`
 	TopP        float32           `json:"top_p,omitempty"`
 	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Stream      bool              `json:"stream,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
+
+	temperatureSet bool // Temperature was given, even as 0
+}
+
+// HasTemperature reports whether the request sets a temperature. A zero
+// temperature only counts when it was given explicitly, in the decoded JSON
+// or through SetTemperature; otherwise the model's default applies.
+func (r *AIRequest) HasTemperature() bool {
+	return r.temperatureSet || r.Temperature != 0
+}
+
+// SetTemperature sets the sampling temperature, marking even 0 as given
+func (r *AIRequest) SetTemperature(temperature float32) {
+	r.Temperature = temperature
+	r.temperatureSet = true
+}
+
+// UnmarshalJSON implements json.Unmarshaler, recording whether the request
+// gives a temperature
+func (r *AIRequest) UnmarshalJSON(data []byte) error {
+	type plain AIRequest
+	decoded := struct {
+		*plain
+		Temperature *float32 `json:"temperature"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Temperature != nil {
+		r.SetTemperature(*decoded.Temperature)
+	}
+	return nil
 }
 
 // AIResponse represents a response from the AI service
@@ -35,6 +70,19 @@ type Choice struct {
 	LogProbs     *float64 `json:"log_probs,omitempty"`
 }
 
+// StreamChunk represents a single token emitted during streaming generation
+type StreamChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Special bool   `json:"special,omitempty"`
+}
+
+// StreamFunc is called for every chunk produced by a streaming generation.
+// Returning an error aborts the stream.
+type StreamFunc func(chunk *StreamChunk) error
+
 // Usage represents token usage statistics
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -46,9 +94,21 @@ type Usage struct {
 type AIService interface {
 	GenerateText(ctx context.Context, req *AIRequest) (*AIResponse, error)
 	GenerateCompletion(ctx context.Context, req *AIRequest) (*AIResponse, error)
-	AnalyzeSentiment(ctx context.Context, text string) (*SentimentResponse, error)
-	SummarizeText(ctx context.Context, text string, maxLength int) (*SummaryResponse, error)
-	ValidateModel(model string) error
+	GenerateTextStream(ctx context.Context, req *AIRequest, fn StreamFunc) (*AIResponse, error)
+	AnalyzeSentiment(ctx context.Context, req *SentimentRequest) (*SentimentResponse, error)
+	SummarizeText(ctx context.Context, req *SummaryRequest) (*SummaryResponse, error)
+	Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error)
+	ClassifyZeroShot(ctx context.Context, req *ClassificationRequest) (*ClassificationResponse, error)
+	ExtractEntities(ctx context.Context, req *EntityRequest) (*EntityResponse, error)
+	Translate(ctx context.Context, req *TranslationRequest) (*TranslationResponse, error)
+	AnswerQuestion(ctx context.Context, question, contextText string) (*QAResponse, error)
+	ValidateModel(ctx context.Context, model string) (*ModelInfo, error)
+}
+
+// SentimentRequest represents a sentiment analysis request
+type SentimentRequest struct {
+	Model string `json:"model,omitempty"` // overrides the configured sentiment model
+	Text  string `json:"text"`
 }
 
 // SentimentResponse represents sentiment analysis result
@@ -57,6 +117,26 @@ type SentimentResponse struct {
 	Sentiment string  `json:"sentiment"`
 	Score     float64 `json:"score"`
 	Confidence float64 `json:"confidence"`
+	Model     string  `json:"model"`
+}
+
+// Summarization strategies
+const (
+	// SummaryStrategyMapReduce splits long text into chunks, summarizes them
+	// concurrently and summarizes the combined summaries until they fit
+	// max_length. Text that fits a single chunk is summarized directly.
+	SummaryStrategyMapReduce = "map_reduce"
+	// SummaryStrategySingle sends the whole text in one request; the model
+	// truncates input beyond its context window
+	SummaryStrategySingle = "single"
+)
+
+// SummaryRequest represents a text summarization request
+type SummaryRequest struct {
+	Model     string `json:"model,omitempty"` // overrides the configured summarization model
+	Text      string `json:"text"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Strategy  string `json:"strategy,omitempty"` // map_reduce (default) or single
 }
 
 // SummaryResponse represents text summarization result
@@ -64,6 +144,10 @@ type SummaryResponse struct {
 	OriginalText string `json:"original_text"`
 	Summary      string `json:"summary"`
 	Compression  float64 `json:"compression"`
+	Model        string `json:"model"`
+	Strategy     string `json:"strategy"`
+	Chunks       int    `json:"chunks"` // number of pieces the input was split into
+	Passes       int    `json:"passes"` // number of summarization rounds
 }
 
 // ErrorResponse represents an error response
@@ -74,8 +158,18 @@ type ErrorResponse struct {
 	Details interface{} `json:"details,omitempty"`
 }
 
+// MaxTemperature is the highest sampling temperature the providers accept,
+// as in the OpenAI API. The native endpoints keep temperatures within 0..1.
+const MaxTemperature = 2
+
 // Validate validates the AI request
 func (r *AIRequest) Validate() error {
+	return r.ValidateWithin(1)
+}
+
+// ValidateWithin validates the AI request, accepting temperatures up to
+// maxTemperature
+func (r *AIRequest) ValidateWithin(maxTemperature float32) error {
 	if r.Prompt == "" {
 		return &ErrorResponse{
 			Code:    400,
@@ -97,10 +191,50 @@ func (r *AIRequest) Validate() error {
 			Type:    "validation_error",
 		}
 	}
-	if r.Temperature < 0 || r.Temperature > 1 {
+	if r.Temperature < 0 || r.Temperature > maxTemperature {
+		return &ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("temperature must be between 0 and %g", maxTemperature),
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+// Validate validates the sentiment analysis request
+func (r *SentimentRequest) Validate() error {
+	if r.Text == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "text is required",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+// Validate validates the summarization request
+func (r *SummaryRequest) Validate() error {
+	if r.Text == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "text is required",
+			Type:    "validation_error",
+		}
+	}
+	if r.MaxLength < 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "max_length must be positive",
+			Type:    "validation_error",
+		}
+	}
+	switch r.Strategy {
+	case "", SummaryStrategyMapReduce, SummaryStrategySingle:
+	default:
 		return &ErrorResponse{
 			Code:    400,
-			Message: "temperature must be between 0 and 1",
+			Message: fmt.Sprintf("strategy must be %q or %q", SummaryStrategyMapReduce, SummaryStrategySingle),
 			Type:    "validation_error",
 		}
 	}
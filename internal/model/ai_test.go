@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -198,6 +199,48 @@ func TestAIRequest_ValidateWithParameters(t *testing.T) {
 	}
 }
 
+func TestAIRequest_ValidateWithin(t *testing.T) {
+	request := AIRequest{Model: "gpt2", Prompt: "Hello", Temperature: 1.5}
+	if err := request.ValidateWithin(MaxTemperature); err != nil {
+		t.Errorf("AIRequest.ValidateWithin() unexpected error = %v", err)
+	}
+
+	request.Temperature = 2.5
+	err := request.ValidateWithin(MaxTemperature)
+	if err == nil || err.Error() != "temperature must be between 0 and 2" {
+		t.Errorf("AIRequest.ValidateWithin() error = %v, want %v", err, "temperature must be between 0 and 2")
+	}
+}
+
+func TestAIRequest_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		wantTemperature float32
+		wantSet         bool
+	}{
+		{name: "omitted temperature", body: `{"model": "gpt2", "prompt": "Hi"}`},
+		{name: "zero temperature", body: `{"model": "gpt2", "prompt": "Hi", "temperature": 0}`, wantSet: true},
+		{name: "temperature", body: `{"model": "gpt2", "prompt": "Hi", "temperature": 0.7}`, wantTemperature: 0.7, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request AIRequest
+			if err := json.Unmarshal([]byte(tt.body), &request); err != nil {
+				t.Fatalf("json.Unmarshal() unexpected error = %v", err)
+			}
+			if request.Model != "gpt2" || request.Prompt != "Hi" {
+				t.Errorf("AIRequest = %+v, fields not decoded", request)
+			}
+			if request.Temperature != tt.wantTemperature || request.HasTemperature() != tt.wantSet {
+				t.Errorf("AIRequest temperature = %v (set %v), want %v (set %v)",
+					request.Temperature, request.HasTemperature(), tt.wantTemperature, tt.wantSet)
+			}
+		})
+	}
+}
+
 func TestChoiceStruct(t *testing.T) {
 	choice := Choice{
 		Index:        1,
@@ -245,6 +288,77 @@ func TestUsageStruct(t *testing.T) {
 	}
 }
 
+func TestTaskRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request interface{ Validate() error }
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid sentiment request",
+			request: &SentimentRequest{Text: "I love this product!"},
+			wantErr: false,
+		},
+		{
+			name:    "sentiment request with model",
+			request: &SentimentRequest{Model: "distilbert/distilbert-base-uncased-finetuned-sst-2-english", Text: "Great"},
+			wantErr: false,
+		},
+		{
+			name:    "sentiment request missing text",
+			request: &SentimentRequest{Model: "some/model"},
+			wantErr: true,
+			errMsg:  "text is required",
+		},
+		{
+			name:    "valid summary request",
+			request: &SummaryRequest{Text: "A long text.", MaxLength: 50},
+			wantErr: false,
+		},
+		{
+			name:    "summary request missing text",
+			request: &SummaryRequest{MaxLength: 50},
+			wantErr: true,
+			errMsg:  "text is required",
+		},
+		{
+			name:    "summary request with strategy",
+			request: &SummaryRequest{Text: "A long text.", Strategy: SummaryStrategySingle},
+			wantErr: false,
+		},
+		{
+			name:    "summary request unknown strategy",
+			request: &SummaryRequest{Text: "A long text.", Strategy: "refine"},
+			wantErr: true,
+			errMsg:  `strategy must be "map_reduce" or "single"`,
+		},
+		{
+			name:    "summary request negative max length",
+			request: &SummaryRequest{Text: "A long text.", MaxLength: -1},
+			wantErr: true,
+			errMsg:  "max_length must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
 func TestSentimentResponse(t *testing.T) {
 	sentiment := SentimentResponse{
 		Text:       "I love this product!",
@@ -352,6 +466,6 @@ func BenchmarkErrorResponse_Error(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err.Error()
+		_ = err.Error()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// BatchRequest represents a set of independent generation requests
+type BatchRequest struct {
+	ID       string      `json:"id"`
+	Requests []AIRequest `json:"requests"`
+}
+
+// BatchResult holds the outcome of a single batch item. Exactly one of
+// Response and Error is set.
+type BatchResult struct {
+	Index    int            `json:"index"`
+	Response *AIResponse    `json:"response,omitempty"`
+	Error    *ErrorResponse `json:"error,omitempty"`
+}
+
+// BatchResponse represents the results of a batch, in request order
+type BatchResponse struct {
+	ID           string        `json:"id"`
+	Results      []BatchResult `json:"results"`
+	Succeeded    int           `json:"succeeded"`
+	Failed       int           `json:"failed"`
+	Usage        Usage         `json:"usage"`
+	GeneratedAt  time.Time     `json:"generated_at"`
+	ProcessingMs int64         `json:"processing_ms"`
+}
+
+// Validate validates the batch as a whole. Individual requests are validated
+// separately so that one bad item does not fail the batch.
+func (r *BatchRequest) Validate(maxSize int) error {
+	if len(r.Requests) == 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "requests is required",
+			Type:    "validation_error",
+		}
+	}
+	if maxSize > 0 && len(r.Requests) > maxSize {
+		return &ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("batch size %d exceeds the maximum of %d", len(r.Requests), maxSize),
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
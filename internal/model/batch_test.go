@@ -0,0 +1,62 @@
+package model
+
+import "testing"
+
+func TestBatchRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request BatchRequest
+		maxSize int
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid batch",
+			request: BatchRequest{Requests: []AIRequest{{Model: "gpt2", Prompt: "a"}, {Model: "gpt2", Prompt: "b"}}},
+			maxSize: 2,
+			wantErr: false,
+		},
+		{
+			name:    "invalid items are not checked",
+			request: BatchRequest{Requests: []AIRequest{{}}},
+			maxSize: 2,
+			wantErr: false,
+		},
+		{
+			name:    "empty batch",
+			request: BatchRequest{},
+			maxSize: 2,
+			wantErr: true,
+			errMsg:  "requests is required",
+		},
+		{
+			name:    "too many requests",
+			request: BatchRequest{Requests: make([]AIRequest, 3)},
+			maxSize: 2,
+			wantErr: true,
+			errMsg:  "batch size 3 exceeds the maximum of 2",
+		},
+		{
+			name:    "unbounded",
+			request: BatchRequest{Requests: make([]AIRequest, 3)},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate(tt.maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("BatchRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("BatchRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("BatchRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Chat message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatMessage represents a single turn in a conversation
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest represents a multi-turn conversation request
+type ChatRequest struct {
+	ID          string                 `json:"id"`
+	Model       string                 `json:"model"`
+	Messages    []ChatMessage          `json:"messages"`
+	MaxTokens   int                    `json:"max_tokens,omitempty"`
+	Temperature *float32               `json:"temperature,omitempty"` // nil for the model's default
+	TopP        float32                `json:"top_p,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Stream      bool                   `json:"stream,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// ChatResponse represents the assistant reply to a conversation
+type ChatResponse struct {
+	ID           string      `json:"id"`
+	Model        string      `json:"model"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+	Usage        Usage       `json:"usage"`
+	GeneratedAt  time.Time   `json:"generated_at"`
+	ProcessingMs int64       `json:"processing_ms"`
+}
+
+// Validate validates the chat request
+func (r *ChatRequest) Validate() error {
+	if len(r.Messages) == 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "messages is required",
+			Type:    "validation_error",
+		}
+	}
+	for i, msg := range r.Messages {
+		switch msg.Role {
+		case RoleSystem, RoleUser, RoleAssistant:
+		default:
+			return &ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("messages[%d]: role must be one of system, user, assistant", i),
+				Type:    "validation_error",
+			}
+		}
+	}
+	if r.Model == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "model is required",
+			Type:    "validation_error",
+		}
+	}
+	if r.MaxTokens < 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "max_tokens must be positive",
+			Type:    "validation_error",
+		}
+	}
+	if r.Temperature != nil && (*r.Temperature < 0 || *r.Temperature > 1) {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "temperature must be between 0 and 1",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+// ToAIRequest converts the chat request into a generation request for an
+// already rendered prompt
+func (r *ChatRequest) ToAIRequest(prompt string) *AIRequest {
+	req := &AIRequest{
+		ID:         r.ID,
+		Model:      r.Model,
+		Prompt:     prompt,
+		MaxTokens:  r.MaxTokens,
+		TopP:       r.TopP,
+		Parameters: r.Parameters,
+		Stream:     r.Stream,
+		CreatedAt:  r.CreatedAt,
+	}
+	if r.Temperature != nil {
+		req.SetTemperature(*r.Temperature)
+	}
+	return req
+}
@@ -0,0 +1,96 @@
+package model
+
+import "testing"
+
+func TestChatRequest_Validate(t *testing.T) {
+	valid := []ChatMessage{{Role: RoleUser, Content: "Hi"}}
+
+	tests := []struct {
+		name    string
+		request ChatRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: ChatRequest{Model: "gpt2", Messages: valid, Temperature: temperature(0.5)},
+			wantErr: false,
+		},
+		{
+			name:    "missing messages",
+			request: ChatRequest{Model: "gpt2"},
+			wantErr: true,
+			errMsg:  "messages is required",
+		},
+		{
+			name: "invalid role",
+			request: ChatRequest{
+				Model:    "gpt2",
+				Messages: []ChatMessage{{Role: "tool", Content: "x"}},
+			},
+			wantErr: true,
+			errMsg:  "messages[0]: role must be one of system, user, assistant",
+		},
+		{
+			name:    "missing model",
+			request: ChatRequest{Messages: valid},
+			wantErr: true,
+			errMsg:  "model is required",
+		},
+		{
+			name:    "temperature too high",
+			request: ChatRequest{Model: "gpt2", Messages: valid, Temperature: temperature(1.5)},
+			wantErr: true,
+			errMsg:  "temperature must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ChatRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("ChatRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("ChatRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestChatRequest_ToAIRequest(t *testing.T) {
+	req := ChatRequest{
+		ID:          "chat-id",
+		Model:       "gpt2",
+		Messages:    []ChatMessage{{Role: RoleUser, Content: "Hi"}},
+		MaxTokens:   50,
+		Temperature: temperature(0),
+		Stream:      true,
+	}
+
+	aiReq := req.ToAIRequest("user: Hi\nassistant:")
+	if aiReq.Prompt != "user: Hi\nassistant:" {
+		t.Errorf("AIRequest.Prompt = %q", aiReq.Prompt)
+	}
+	if aiReq.ID != "chat-id" || aiReq.Model != "gpt2" || aiReq.MaxTokens != 50 || !aiReq.Stream {
+		t.Errorf("AIRequest = %+v, fields not copied", aiReq)
+	}
+	if !aiReq.HasTemperature() {
+		t.Error("AIRequest.HasTemperature() = false for an explicit temperature of 0")
+	}
+
+	req.Temperature = nil
+	if req.ToAIRequest("user: Hi\nassistant:").HasTemperature() {
+		t.Error("AIRequest.HasTemperature() = true without a temperature")
+	}
+}
+
+// temperature returns a pointer to a request temperature
+func temperature(t float32) *float32 {
+	return &t
+}
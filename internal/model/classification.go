@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxCandidateLabels bounds the labels of a zero-shot request; every label
+// costs one entailment inference upstream
+const MaxCandidateLabels = 32
+
+// ClassificationRequest represents a zero-shot classification request
+type ClassificationRequest struct {
+	Model              string   `json:"model,omitempty"`
+	Text               string   `json:"text"`
+	Labels             []string `json:"labels"`
+	HypothesisTemplate string   `json:"hypothesis_template,omitempty"` // e.g. "This ticket is about {}."
+	MultiLabel         bool     `json:"multi_label,omitempty"`
+}
+
+// LabelScore is a candidate label and its score
+type LabelScore struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// ClassificationResponse represents zero-shot classification results. Labels
+// are ranked by descending score. Without multi_label the scores sum to 1;
+// with it each score is independent.
+type ClassificationResponse struct {
+	Text       string       `json:"text"`
+	Model      string       `json:"model"`
+	Labels     []LabelScore `json:"labels"`
+	MultiLabel bool         `json:"multi_label"`
+}
+
+// Validate validates the classification request
+func (r *ClassificationRequest) Validate() error {
+	if r.Text == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "text is required",
+			Type:    "validation_error",
+		}
+	}
+	if len(r.Labels) == 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "labels is required",
+			Type:    "validation_error",
+		}
+	}
+	if len(r.Labels) > MaxCandidateLabels {
+		return &ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("labels must not contain more than %d items", MaxCandidateLabels),
+			Type:    "validation_error",
+		}
+	}
+	seen := make(map[string]bool, len(r.Labels))
+	for i, label := range r.Labels {
+		if strings.TrimSpace(label) == "" {
+			return &ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("labels[%d] must not be empty", i),
+				Type:    "validation_error",
+			}
+		}
+		if seen[label] {
+			return &ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("labels[%d] is a duplicate of %q", i, label),
+				Type:    "validation_error",
+			}
+		}
+		seen[label] = true
+	}
+	if r.HypothesisTemplate != "" && !strings.Contains(r.HypothesisTemplate, "{}") {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "hypothesis_template must contain {} where the label is inserted",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package model
+
+import "testing"
+
+func TestClassificationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request ClassificationRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: ClassificationRequest{Text: "My invoice is wrong", Labels: []string{"billing", "shipping"}, HypothesisTemplate: "This ticket is about {}."},
+			wantErr: false,
+		},
+		{
+			name:    "missing text",
+			request: ClassificationRequest{Labels: []string{"billing"}},
+			wantErr: true,
+			errMsg:  "text is required",
+		},
+		{
+			name:    "missing labels",
+			request: ClassificationRequest{Text: "x"},
+			wantErr: true,
+			errMsg:  "labels is required",
+		},
+		{
+			name:    "too many labels",
+			request: ClassificationRequest{Text: "x", Labels: make([]string, MaxCandidateLabels+1)},
+			wantErr: true,
+			errMsg:  "labels must not contain more than 32 items",
+		},
+		{
+			name:    "blank label",
+			request: ClassificationRequest{Text: "x", Labels: []string{"billing", " "}},
+			wantErr: true,
+			errMsg:  "labels[1] must not be empty",
+		},
+		{
+			name:    "duplicate label",
+			request: ClassificationRequest{Text: "x", Labels: []string{"billing", "billing"}},
+			wantErr: true,
+			errMsg:  `labels[1] is a duplicate of "billing"`,
+		},
+		{
+			name:    "template without placeholder",
+			request: ClassificationRequest{Text: "x", Labels: []string{"billing"}, HypothesisTemplate: "This is about"},
+			wantErr: true,
+			errMsg:  "hypothesis_template must contain {} where the label is inserted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ClassificationRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("ClassificationRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("ClassificationRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MaxEmbeddingInputs bounds the number of inputs embedded in one request
+const MaxEmbeddingInputs = 128
+
+// Pooling strategies for models that return token-level embeddings
+const (
+	PoolingMean = "mean"
+	PoolingCLS  = "cls"
+)
+
+// EmbeddingInput accepts either a single string or an array of strings, as
+// the OpenAI embeddings API does
+type EmbeddingInput []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (in *EmbeddingInput) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*in = EmbeddingInput{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("input must be a string or an array of strings")
+	}
+	*in = multiple
+	return nil
+}
+
+// EmbeddingRequest represents a request to embed one or more texts
+type EmbeddingRequest struct {
+	ID        string         `json:"id,omitempty"`
+	Model     string         `json:"model"`
+	Input     EmbeddingInput `json:"input"`
+	Normalize bool           `json:"normalize,omitempty"` // L2-normalize each vector
+	Pooling   string         `json:"pooling,omitempty"`   // applied to token-level output; defaults to mean
+}
+
+// Embedding is the vector for a single input
+type Embedding struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float64 `json:"embedding"`
+}
+
+// EmbeddingResponse represents the vectors for every input, in input order.
+// The shape follows the OpenAI embeddings API.
+type EmbeddingResponse struct {
+	Object       string      `json:"object"`
+	Data         []Embedding `json:"data"`
+	Model        string      `json:"model"`
+	Usage        Usage       `json:"usage"`
+	ProcessingMs int64       `json:"processing_ms"`
+}
+
+// Validate validates the embedding request
+func (r *EmbeddingRequest) Validate() error {
+	if r.Model == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "model is required",
+			Type:    "validation_error",
+		}
+	}
+	if len(r.Input) == 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "input is required",
+			Type:    "validation_error",
+		}
+	}
+	if len(r.Input) > MaxEmbeddingInputs {
+		return &ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("input must not contain more than %d items", MaxEmbeddingInputs),
+			Type:    "validation_error",
+		}
+	}
+	for i, text := range r.Input {
+		if text == "" {
+			return &ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("input[%d] must not be empty", i),
+				Type:    "validation_error",
+			}
+		}
+	}
+	switch r.Pooling {
+	case "", PoolingMean, PoolingCLS:
+	default:
+		return &ErrorResponse{
+			Code:    400,
+			Message: "pooling must be one of mean, cls",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
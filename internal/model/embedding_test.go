@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddingInput_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    EmbeddingInput
+		wantErr bool
+	}{
+		{name: "single string", data: `"hello"`, want: EmbeddingInput{"hello"}},
+		{name: "array", data: `["a", "b"]`, want: EmbeddingInput{"a", "b"}},
+		{name: "number", data: `42`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got EmbeddingInput
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("UnmarshalJSON() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request EmbeddingRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: EmbeddingRequest{Model: "sentence-transformers/all-MiniLM-L6-v2", Input: EmbeddingInput{"a", "b"}, Pooling: PoolingCLS},
+			wantErr: false,
+		},
+		{
+			name:    "missing model",
+			request: EmbeddingRequest{Input: EmbeddingInput{"a"}},
+			wantErr: true,
+			errMsg:  "model is required",
+		},
+		{
+			name:    "missing input",
+			request: EmbeddingRequest{Model: "m"},
+			wantErr: true,
+			errMsg:  "input is required",
+		},
+		{
+			name:    "too many inputs",
+			request: EmbeddingRequest{Model: "m", Input: make(EmbeddingInput, MaxEmbeddingInputs+1)},
+			wantErr: true,
+			errMsg:  "input must not contain more than 128 items",
+		},
+		{
+			name:    "empty input item",
+			request: EmbeddingRequest{Model: "m", Input: EmbeddingInput{"a", ""}},
+			wantErr: true,
+			errMsg:  "input[1] must not be empty",
+		},
+		{
+			name:    "unknown pooling",
+			request: EmbeddingRequest{Model: "m", Input: EmbeddingInput{"a"}, Pooling: "max"},
+			wantErr: true,
+			errMsg:  "pooling must be one of mean, cls",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("EmbeddingRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("EmbeddingRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("EmbeddingRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+// Aggregation strategies for grouping sub-word tokens into entities, as
+// defined by the Hugging Face token-classification pipeline
+const (
+	AggregationNone    = "none"
+	AggregationSimple  = "simple"
+	AggregationFirst   = "first"
+	AggregationAverage = "average"
+	AggregationMax     = "max"
+)
+
+// EntityRequest represents a named entity recognition request
+type EntityRequest struct {
+	Model               string  `json:"model,omitempty"`
+	Text                string  `json:"text"`
+	AggregationStrategy string  `json:"aggregation_strategy,omitempty"` // defaults to simple
+	MinScore            float64 `json:"min_score,omitempty"`            // drop entities scored below this
+}
+
+// Entity is a span of the input text recognized as an entity. Start and End
+// are character (Unicode code point) offsets into the input, End exclusive;
+// both are -1 when the model reports no offsets.
+type Entity struct {
+	Type  string  `json:"type"`
+	Text  string  `json:"text"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Score float64 `json:"score"`
+}
+
+// EntityResponse represents the entities found in a text, in text order
+type EntityResponse struct {
+	Text     string   `json:"text"`
+	Model    string   `json:"model"`
+	Entities []Entity `json:"entities"`
+}
+
+// Validate validates the entity request
+func (r *EntityRequest) Validate() error {
+	if r.Text == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "text is required",
+			Type:    "validation_error",
+		}
+	}
+	switch r.AggregationStrategy {
+	case "", AggregationNone, AggregationSimple, AggregationFirst, AggregationAverage, AggregationMax:
+	default:
+		return &ErrorResponse{
+			Code:    400,
+			Message: "aggregation_strategy must be one of none, simple, first, average, max",
+			Type:    "validation_error",
+		}
+	}
+	if r.MinScore < 0 || r.MinScore > 1 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "min_score must be between 0 and 1",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
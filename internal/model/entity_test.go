@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func TestEntityRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request EntityRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: EntityRequest{Text: "Ada Lovelace was born in London", AggregationStrategy: AggregationFirst, MinScore: 0.5},
+			wantErr: false,
+		},
+		{
+			name:    "default strategy",
+			request: EntityRequest{Text: "x"},
+			wantErr: false,
+		},
+		{
+			name:    "missing text",
+			request: EntityRequest{},
+			wantErr: true,
+			errMsg:  "text is required",
+		},
+		{
+			name:    "unknown strategy",
+			request: EntityRequest{Text: "x", AggregationStrategy: "longest"},
+			wantErr: true,
+			errMsg:  "aggregation_strategy must be one of none, simple, first, average, max",
+		},
+		{
+			name:    "invalid min score",
+			request: EntityRequest{Text: "x", MinScore: 1.5},
+			wantErr: true,
+			errMsg:  "min_score must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("EntityRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("EntityRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("EntityRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
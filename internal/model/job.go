@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// Job types accepted by the asynchronous job API
+const (
+	JobTypeGenerate  = "generate"
+	JobTypeSentiment = "sentiment"
+	JobTypeSummarize = "summarize"
+)
+
+// Job statuses
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// JobRequest represents a request to run an operation asynchronously. Input
+// holds the operation's usual request body: an AIRequest for generate, a
+// TextInput for sentiment and summarize.
+type JobRequest struct {
+	Type        string          `json:"type"`
+	Input       json.RawMessage `json:"input"`
+	CallbackURL string          `json:"callback_url,omitempty"`
+}
+
+// TextInput is the input of the sentiment and summarize operations
+type TextInput struct {
+	Model     string `json:"model,omitempty"`
+	Text      string `json:"text"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Strategy  string `json:"strategy,omitempty"`
+}
+
+// Job represents an asynchronous operation and, once finished, its outcome
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Status      string          `json:"status"`
+	Input       json.RawMessage `json:"input"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *ErrorResponse  `json:"error,omitempty"`
+	CallbackURL string          `json:"callback_url,omitempty"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the job has finished
+func (j *Job) Done() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
+
+// Validate validates the job request envelope. The input is validated by the
+// operation when the job is submitted.
+func (r *JobRequest) Validate() error {
+	switch r.Type {
+	case JobTypeGenerate, JobTypeSentiment, JobTypeSummarize:
+	case "":
+		return &ErrorResponse{
+			Code:    400,
+			Message: "type is required",
+			Type:    "validation_error",
+		}
+	default:
+		return &ErrorResponse{
+			Code:    400,
+			Message: "type must be one of generate, sentiment, summarize",
+			Type:    "validation_error",
+		}
+	}
+	if len(r.Input) == 0 {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "input is required",
+			Type:    "validation_error",
+		}
+	}
+	if r.CallbackURL != "" {
+		u, err := url.Parse(r.CallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ErrorResponse{
+				Code:    400,
+				Message: "callback_url must be an absolute http or https URL",
+				Type:    "validation_error",
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJobRequest_Validate(t *testing.T) {
+	input := json.RawMessage(`{"text": "hello"}`)
+
+	tests := []struct {
+		name    string
+		request JobRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: JobRequest{Type: JobTypeSummarize, Input: input, CallbackURL: "https://example.com/hook"},
+			wantErr: false,
+		},
+		{
+			name:    "missing type",
+			request: JobRequest{Input: input},
+			wantErr: true,
+			errMsg:  "type is required",
+		},
+		{
+			name:    "unknown type",
+			request: JobRequest{Type: "translate", Input: input},
+			wantErr: true,
+			errMsg:  "type must be one of generate, sentiment, summarize",
+		},
+		{
+			name:    "missing input",
+			request: JobRequest{Type: JobTypeGenerate},
+			wantErr: true,
+			errMsg:  "input is required",
+		},
+		{
+			name:    "relative callback",
+			request: JobRequest{Type: JobTypeSentiment, Input: input, CallbackURL: "/hook"},
+			wantErr: true,
+			errMsg:  "callback_url must be an absolute http or https URL",
+		},
+		{
+			name:    "non-http callback",
+			request: JobRequest{Type: JobTypeSentiment, Input: input, CallbackURL: "file:///etc/passwd"},
+			wantErr: true,
+			errMsg:  "callback_url must be an absolute http or https URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("JobRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("JobRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("JobRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestJob_Done(t *testing.T) {
+	for status, want := range map[string]bool{
+		JobStatusQueued:    false,
+		JobStatusRunning:   false,
+		JobStatusSucceeded: true,
+		JobStatusFailed:    true,
+	} {
+		job := &Job{Status: status}
+		if got := job.Done(); got != want {
+			t.Errorf("Job{Status: %q}.Done() = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Sources of model metadata
+const (
+	ModelSourceHub      = "hub"      // fetched from the Hugging Face Hub
+	ModelSourceCatalog  = "catalog"  // served from the local catalog while the Hub is unreachable
+	ModelSourceProvider = "provider" // served by a backend without Hub metadata
+)
+
+// ModelInfo describes a model as reported by the Hugging Face Hub
+type ModelInfo struct {
+	ID          string     `json:"id"`
+	Provider    string     `json:"provider"`
+	PipelineTag string     `json:"pipeline_tag,omitempty"`
+	License     string     `json:"license,omitempty"`
+	LibraryName string     `json:"library_name,omitempty"`
+	Gated       bool       `json:"gated"`
+	Private     bool       `json:"private"`
+	Deployable  bool       `json:"deployable"` // served by the serverless Inference API
+	Source      string     `json:"source"`
+	FetchedAt   *time.Time `json:"fetched_at,omitempty"` // nil for models without Hub metadata
+}
+
+// RegisteredModel describes a model advertised by this server
+type RegisteredModel struct {
+	ID               string                 `json:"id"`
+	Object           string                 `json:"object"` // always "model"
+	Task             string                 `json:"task"`
+	Provider         string                 `json:"provider"`
+	MaxContextLength int                    `json:"max_context_length,omitempty"`
+	Parameters       map[string]interface{} `json:"parameters,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+}
+
+// ModelList is the response of the model listing endpoint, shaped like the
+// OpenAI models API
+type ModelList struct {
+	Object string            `json:"object"` // always "list"
+	Data   []RegisteredModel `json:"data"`
+}
+
+// CircuitBreakerStatus reports the circuit breaker of a model
+type CircuitBreakerStatus struct {
+	Model    string     `json:"model"`
+	State    string     `json:"state"`    // closed, half_open or open
+	Requests int        `json:"requests"` // requests in the current window
+	Failures int        `json:"failures"` // failed requests in the current window
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// OpenAIChatCompletionRequest represents an OpenAI-style chat completion request
+type OpenAIChatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        float32       `json:"top_p,omitempty"`
+	N           int           `json:"n,omitempty"`
+	Stop        StopSequences `json:"stop,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+	User        string        `json:"user,omitempty"`
+}
+
+// OpenAICompletionRequest represents an OpenAI-style text completion request
+type OpenAICompletionRequest struct {
+	Model       string        `json:"model"`
+	Prompt      string        `json:"prompt"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        float32       `json:"top_p,omitempty"`
+	N           int           `json:"n,omitempty"`
+	Stop        StopSequences `json:"stop,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+	User        string        `json:"user,omitempty"`
+}
+
+// StopSequences accepts either a single string or an array of strings, as the
+// OpenAI API does for the stop parameter
+type StopSequences []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *StopSequences) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single != "" {
+			*s = StopSequences{single}
+		}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("stop must be a string or an array of strings")
+	}
+	*s = multiple
+	return nil
+}
+
+// OpenAIUsage represents token usage in OpenAI responses
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIChatChoice represents a single choice in a chat completion response
+type OpenAIChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionResponse represents an OpenAI-style chat completion response
+type OpenAIChatCompletionResponse struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []OpenAIChatChoice `json:"choices"`
+	Usage   OpenAIUsage        `json:"usage"`
+}
+
+// OpenAIChatDelta represents the incremental message content in a stream chunk
+type OpenAIChatDelta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// OpenAIChatChunkChoice represents a single choice in a chat completion stream chunk
+type OpenAIChatChunkChoice struct {
+	Index        int             `json:"index"`
+	Delta        OpenAIChatDelta `json:"delta"`
+	FinishReason *string         `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionChunk represents a streamed chat completion chunk
+type OpenAIChatCompletionChunk struct {
+	ID      string                  `json:"id"`
+	Object  string                  `json:"object"`
+	Created int64                   `json:"created"`
+	Model   string                  `json:"model"`
+	Choices []OpenAIChatChunkChoice `json:"choices"`
+	Usage   *OpenAIUsage            `json:"usage,omitempty"`
+}
+
+// OpenAICompletionChoice represents a single choice in a text completion response
+type OpenAICompletionChoice struct {
+	Index        int         `json:"index"`
+	Text         string      `json:"text"`
+	Logprobs     interface{} `json:"logprobs"`
+	FinishReason *string     `json:"finish_reason"`
+}
+
+// OpenAICompletionResponse represents an OpenAI-style text completion response
+// or stream chunk
+type OpenAICompletionResponse struct {
+	ID      string                   `json:"id"`
+	Object  string                   `json:"object"`
+	Created int64                    `json:"created"`
+	Model   string                   `json:"model"`
+	Choices []OpenAICompletionChoice `json:"choices"`
+	Usage   *OpenAIUsage             `json:"usage,omitempty"`
+}
+
+// OpenAIErrorResponse represents an error in the OpenAI wire format
+type OpenAIErrorResponse struct {
+	Error OpenAIError `json:"error"`
+}
+
+// OpenAIError holds the details of an OpenAI-style error
+type OpenAIError struct {
+	Message string      `json:"message"`
+	Type    string      `json:"type"`
+	Param   *string     `json:"param"`
+	Code    interface{} `json:"code"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStopSequences_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "single string",
+			input: `{"stop": "\n"}`,
+			want:  []string{"\n"},
+		},
+		{
+			name:  "array of strings",
+			input: `{"stop": ["###", "User:"]}`,
+			want:  []string{"###", "User:"},
+		},
+		{
+			name:  "empty string",
+			input: `{"stop": ""}`,
+			want:  nil,
+		},
+		{
+			name:  "omitted",
+			input: `{}`,
+			want:  nil,
+		},
+		{
+			name:    "invalid type",
+			input:   `{"stop": 42}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req OpenAICompletionRequest
+			err := json.Unmarshal([]byte(tt.input), &req)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() unexpected error = %v", err)
+			}
+			if len(req.Stop) != len(tt.want) {
+				t.Fatalf("Stop = %v, want %v", req.Stop, tt.want)
+			}
+			for i := range tt.want {
+				if req.Stop[i] != tt.want[i] {
+					t.Errorf("Stop[%d] = %q, want %q", i, req.Stop[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+// QARequest represents an extractive question answering request
+type QARequest struct {
+	Question string `json:"question"`
+	Context  string `json:"context"`
+}
+
+// QAResponse represents the answer span found in the context. Start and End
+// are character offsets into the context (end exclusive), or -1 when the
+// model found no answer.
+type QAResponse struct {
+	Question string  `json:"question"`
+	Answer   string  `json:"answer"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Score    float64 `json:"score"`
+	Model    string  `json:"model"`
+	Windows  int     `json:"windows"` // number of context windows searched
+}
+
+// Validate validates the question answering request
+func (r *QARequest) Validate() error {
+	if r.Question == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "question is required",
+			Type:    "validation_error",
+		}
+	}
+	if r.Context == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "context is required",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
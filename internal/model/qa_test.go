@@ -0,0 +1,47 @@
+package model
+
+import "testing"
+
+func TestQARequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request QARequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: QARequest{Question: "Who wrote it?", Context: "It was written by Ada."},
+			wantErr: false,
+		},
+		{
+			name:    "missing question",
+			request: QARequest{Context: "It was written by Ada."},
+			wantErr: true,
+			errMsg:  "question is required",
+		},
+		{
+			name:    "missing context",
+			request: QARequest{Question: "Who wrote it?"},
+			wantErr: true,
+			errMsg:  "context is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("QARequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("QARequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("QARequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "regexp"
+
+// languageCode matches ISO 639 codes ("en", "deu") optionally followed by a
+// script or region ("zh-Hant", "eng_Latn")
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}([_-][A-Za-z]{2,4})?$`)
+
+// TranslationRequest represents a translation request
+type TranslationRequest struct {
+	Model      string `json:"model,omitempty"` // overrides the model configured for the language pair
+	Text       string `json:"text"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// TranslationResponse represents translation result
+type TranslationResponse struct {
+	OriginalText   string `json:"original_text"`
+	TranslatedText string `json:"translated_text"`
+	SourceLang     string `json:"source_lang"`
+	TargetLang     string `json:"target_lang"`
+	Model          string `json:"model"`
+	Chunks         int    `json:"chunks"` // number of pieces the input was split into
+}
+
+// Validate validates the translation request
+func (r *TranslationRequest) Validate() error {
+	if r.Text == "" {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "text is required",
+			Type:    "validation_error",
+		}
+	}
+	if !languageCode.MatchString(r.SourceLang) {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "source_lang must be a language code such as \"en\"",
+			Type:    "validation_error",
+		}
+	}
+	if !languageCode.MatchString(r.TargetLang) {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "target_lang must be a language code such as \"de\"",
+			Type:    "validation_error",
+		}
+	}
+	if r.SourceLang == r.TargetLang {
+		return &ErrorResponse{
+			Code:    400,
+			Message: "source_lang and target_lang must differ",
+			Type:    "validation_error",
+		}
+	}
+	return nil
+}
+
+// LanguagePair returns the "source-target" key used to configure translation models
+func (r *TranslationRequest) LanguagePair() string {
+	return r.SourceLang + "-" + r.TargetLang
+}
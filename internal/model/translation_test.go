@@ -0,0 +1,64 @@
+package model
+
+import "testing"
+
+func TestTranslationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request TranslationRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			request: TranslationRequest{Text: "Hello", SourceLang: "en", TargetLang: "de"},
+			wantErr: false,
+		},
+		{
+			name:    "flores codes",
+			request: TranslationRequest{Text: "Hello", SourceLang: "eng_Latn", TargetLang: "zho_Hans"},
+			wantErr: false,
+		},
+		{
+			name:    "missing text",
+			request: TranslationRequest{SourceLang: "en", TargetLang: "de"},
+			wantErr: true,
+			errMsg:  "text is required",
+		},
+		{
+			name:    "invalid source",
+			request: TranslationRequest{Text: "Hello", SourceLang: "English", TargetLang: "de"},
+			wantErr: true,
+			errMsg:  `source_lang must be a language code such as "en"`,
+		},
+		{
+			name:    "missing target",
+			request: TranslationRequest{Text: "Hello", SourceLang: "en"},
+			wantErr: true,
+			errMsg:  `target_lang must be a language code such as "de"`,
+		},
+		{
+			name:    "same language",
+			request: TranslationRequest{Text: "Hello", SourceLang: "en", TargetLang: "en"},
+			wantErr: true,
+			errMsg:  "source_lang and target_lang must differ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("TranslationRequest.Validate() expected error but got nil")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("TranslationRequest.Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("TranslationRequest.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle, fully refilled buckets are discarded
+const sweepInterval = time.Minute
+
+// Limiter is a keyed token bucket. Each key holds up to capacity tokens which
+// refill continuously at capacity per minute. Charges are made up front with
+// Reserve and corrected afterwards with Reservation.Reconcile, so a bucket
+// can go into debt when actual usage exceeds the estimate.
+type Limiter struct {
+	mu        sync.Mutex
+	capacity  float64
+	perSecond float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Status describes a bucket after a reservation attempt
+type Status struct {
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until the rejected charge would fit; zero when allowed
+}
+
+// NewLimiter creates a limiter allowing perMinute tokens per key per minute
+func NewLimiter(perMinute int) *Limiter {
+	return &Limiter{
+		capacity:  float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		buckets:   make(map[string]*bucket),
+		now:       time.Now,
+	}
+}
+
+// Reserve charges n tokens to key. Charges larger than the bucket capacity
+// are capped at the capacity so that oversized requests can still run once
+// the bucket is full. When the bucket cannot cover the charge nothing is
+// deducted and the returned reservation is nil.
+func (l *Limiter) Reserve(key string, n int) (*Reservation, Status) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	b := l.refill(key, now)
+
+	charge := math.Min(float64(n), l.capacity)
+	if charge > b.tokens {
+		missing := charge - b.tokens
+		status := l.status(b)
+		status.RetryAfter = time.Duration(missing / l.perSecond * float64(time.Second))
+		return nil, status
+	}
+
+	b.tokens -= charge
+	return &Reservation{limiter: l, key: key, charged: int(charge)}, l.status(b)
+}
+
+// Status returns the current state of the bucket for key without charging it
+func (l *Limiter) Status(key string) Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status(l.refill(key, l.now()))
+}
+
+// adjust adds delta tokens to the bucket for key; negative deltas charge it
+func (l *Limiter) adjust(key string, delta float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key, l.now())
+	b.tokens = math.Min(b.tokens+delta, l.capacity)
+}
+
+// refill returns the bucket for key topped up for the time elapsed
+func (l *Limiter) refill(key string, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.capacity, updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.tokens+elapsed*l.perSecond, l.capacity)
+		b.updated = now
+	}
+	return b
+}
+
+func (l *Limiter) status(b *bucket) Status {
+	remaining := int(math.Max(0, math.Floor(b.tokens)))
+	reset := time.Duration((l.capacity - b.tokens) / l.perSecond * float64(time.Second))
+	return Status{Limit: int(l.capacity), Remaining: remaining, Reset: reset}
+}
+
+// sweep discards buckets that have refilled completely, as they are
+// indistinguishable from new ones
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.perSecond >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Reservation is a charge made against a limiter that can be corrected once
+// the actual cost is known
+type Reservation struct {
+	mu      sync.Mutex
+	limiter *Limiter
+	key     string
+	charged int
+}
+
+// Reconcile replaces the up-front charge with the actual cost, refunding or
+// charging the difference. It is safe to call on a nil reservation.
+func (r *Reservation) Reconcile(actual int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delta := r.charged - actual
+	r.charged = actual
+	r.mu.Unlock()
+
+	if delta != 0 {
+		r.limiter.adjust(r.key, float64(delta))
+	}
+}
+
+// Reservations is the set of charges made for a single request, e.g. against
+// a global and a per-tenant limit
+type Reservations []*Reservation
+
+// Reconcile reconciles every reservation with the actual cost
+func (rs Reservations) Reconcile(actual int) {
+	for _, r := range rs {
+		r.Reconcile(actual)
+	}
+}
+
+type reservationsKey struct{}
+
+// WithReservation returns a context carrying r in addition to any
+// reservations already present
+func WithReservation(ctx context.Context, r *Reservation) context.Context {
+	existing := ReservationsFromContext(ctx)
+	rs := make(Reservations, 0, len(existing)+1)
+	rs = append(append(rs, existing...), r)
+	return context.WithValue(ctx, reservationsKey{}, rs)
+}
+
+// ReservationsFromContext returns the reservations carried by ctx
+func ReservationsFromContext(ctx context.Context) Reservations {
+	rs, _ := ctx.Value(reservationsKey{}).(Reservations)
+	return rs
+}
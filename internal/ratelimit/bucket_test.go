@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(perMinute int) (*Limiter, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(perMinute)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	l, _ := newTestLimiter(600)
+
+	r, status := l.Reserve("client", 500)
+	if r == nil {
+		t.Fatalf("Reserve(500) rejected, want allowed")
+	}
+	if status.Limit != 600 || status.Remaining != 100 {
+		t.Errorf("Status = %+v, want Limit 600 Remaining 100", status)
+	}
+
+	r, status = l.Reserve("client", 200)
+	if r != nil {
+		t.Fatalf("Reserve(200) allowed, want rejected")
+	}
+	// 100 tokens missing at 10 tokens/second
+	if status.RetryAfter != 10*time.Second {
+		t.Errorf("RetryAfter = %v, want %v", status.RetryAfter, 10*time.Second)
+	}
+	if status.Remaining != 100 {
+		t.Errorf("Remaining after rejection = %d, want 100 (nothing charged)", status.Remaining)
+	}
+
+	// Other keys have their own bucket
+	if r, _ := l.Reserve("other", 600); r == nil {
+		t.Errorf("Reserve on separate key rejected, want allowed")
+	}
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	l, now := newTestLimiter(60)
+
+	if r, _ := l.Reserve("client", 60); r == nil {
+		t.Fatalf("Reserve(60) rejected, want allowed")
+	}
+	if r, _ := l.Reserve("client", 1); r != nil {
+		t.Fatalf("Reserve(1) on empty bucket allowed, want rejected")
+	}
+
+	*now = now.Add(10 * time.Second)
+	status := l.Status("client")
+	if status.Remaining != 10 {
+		t.Errorf("Remaining after 10s = %d, want 10", status.Remaining)
+	}
+	if status.Reset != 50*time.Second {
+		t.Errorf("Reset = %v, want %v", status.Reset, 50*time.Second)
+	}
+
+	*now = now.Add(time.Hour)
+	if status := l.Status("client"); status.Remaining != 60 {
+		t.Errorf("Remaining after an hour = %d, want capped at 60", status.Remaining)
+	}
+}
+
+func TestLimiter_OversizedCharge(t *testing.T) {
+	l, _ := newTestLimiter(100)
+
+	r, status := l.Reserve("client", 1000)
+	if r == nil {
+		t.Fatalf("oversized Reserve rejected on full bucket, want allowed")
+	}
+	if status.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", status.Remaining)
+	}
+}
+
+func TestReservation_Reconcile(t *testing.T) {
+	l, _ := newTestLimiter(600)
+
+	r, _ := l.Reserve("client", 100)
+	r.Reconcile(40) // refund 60
+	if got := l.Status("client").Remaining; got != 560 {
+		t.Errorf("Remaining after refund = %d, want 560", got)
+	}
+
+	r.Reconcile(700) // charge 660 more, bucket goes into debt
+	if got := l.Status("client").Remaining; got != 0 {
+		t.Errorf("Remaining after overrun = %d, want 0", got)
+	}
+	if r, _ := l.Reserve("client", 1); r != nil {
+		t.Errorf("Reserve while in debt allowed, want rejected")
+	}
+
+	var nilReservation *Reservation
+	nilReservation.Reconcile(10) // must not panic
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l, now := newTestLimiter(60)
+
+	l.Reserve("a", 30)
+	*now = now.Add(2 * time.Minute)
+	l.Reserve("b", 1)
+
+	l.mu.Lock()
+	_, ok := l.buckets["a"]
+	l.mu.Unlock()
+	if ok {
+		t.Errorf("refilled bucket was not swept")
+	}
+}
+
+func TestReservationContext(t *testing.T) {
+	if got := ReservationsFromContext(context.Background()); len(got) != 0 {
+		t.Errorf("ReservationsFromContext() on empty context = %v, want empty", got)
+	}
+
+	global, _ := newTestLimiter(100)
+	tenant, _ := newTestLimiter(100)
+	r1, _ := global.Reserve("client", 50)
+	r2, _ := tenant.Reserve("tenant", 50)
+
+	ctx := WithReservation(context.Background(), r1)
+	ctx = WithReservation(ctx, r2)
+
+	rs := ReservationsFromContext(ctx)
+	if len(rs) != 2 || rs[0] != r1 || rs[1] != r2 {
+		t.Fatalf("ReservationsFromContext() = %v, want [r1 r2]", rs)
+	}
+
+	rs.Reconcile(10)
+	if got := global.Status("client").Remaining; got != 90 {
+		t.Errorf("global Remaining = %d, want 90", got)
+	}
+	if got := tenant.Status("tenant").Remaining; got != 90 {
+		t.Errorf("tenant Remaining = %d, want 90", got)
+	}
+}
@@ -0,0 +1,124 @@
+package tokenizer
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+// Counter counts tokens with the tokenizer of each model, loaded on first use
+// from <dir>/<model>/tokenizer.json. Models without a usable tokenizer fall
+// back to Estimate, as does a nil Counter.
+type Counter struct {
+	dir    string
+	logger logger.Logger
+
+	mu      sync.Mutex
+	entries map[string]*counterEntry
+}
+
+// counterEntry holds the tokenizer of a model once loaded; tokenizer stays
+// nil when the model has none
+type counterEntry struct {
+	once      sync.Once
+	tokenizer *Tokenizer
+}
+
+// NewCounter creates a counter loading tokenizers from dir. An empty dir
+// disables tokenizers.
+func NewCounter(dir string, logger logger.Logger) *Counter {
+	return &Counter{
+		dir:     dir,
+		logger:  logger,
+		entries: make(map[string]*counterEntry),
+	}
+}
+
+// Estimate approximates the token count of text (1 token ≈ 4 characters)
+func Estimate(text string) int {
+	return len(text) / 4
+}
+
+// Tokenizer returns the tokenizer of the model, or nil when there is none
+func (c *Counter) Tokenizer(modelName string) *Tokenizer {
+	if c == nil || c.dir == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[modelName]
+	if !ok {
+		entry = &counterEntry{}
+		c.entries[modelName] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.tokenizer = c.load(modelName)
+	})
+	return entry.tokenizer
+}
+
+// load reads the tokenizer of the model. Missing files are expected; files
+// that cannot be used are logged once.
+func (c *Counter) load(modelName string) *Tokenizer {
+	clean := path.Clean(modelName)
+	if modelName == "" || clean != modelName || clean == ".." || path.IsAbs(clean) || strings.HasPrefix(clean, "../") {
+		return nil
+	}
+
+	file := filepath.Join(c.dir, filepath.FromSlash(modelName), "tokenizer.json")
+	tokenizer, err := Load(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		c.logger.Warn(context.Background(), "Failed to load tokenizer, estimating token counts", map[string]interface{}{
+			"model": modelName,
+			"file":  file,
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	c.logger.Info(context.Background(), "Loaded tokenizer", map[string]interface{}{
+		"model": modelName,
+		"file":  file,
+	})
+	return tokenizer
+}
+
+// Count returns the number of tokens of text generated by the model
+func (c *Counter) Count(modelName, text string) int {
+	if tokenizer := c.Tokenizer(modelName); tokenizer != nil {
+		return tokenizer.Count(text)
+	}
+	return Estimate(text)
+}
+
+// CountInput returns the number of tokens of text given to the model as
+// input, including the special tokens its tokenizer adds
+func (c *Counter) CountInput(modelName, text string) int {
+	if tokenizer := c.Tokenizer(modelName); tokenizer != nil {
+		return tokenizer.Count(text) + tokenizer.SpecialTokens()
+	}
+	return Estimate(text)
+}
+
+// Usage returns the token usage of a prompt and the completions generated
+// for it
+func (c *Counter) Usage(modelName, prompt string, completions ...string) model.Usage {
+	usage := model.Usage{PromptTokens: c.CountInput(modelName, prompt)}
+	for _, completion := range completions {
+		usage.CompletionTokens += c.Count(modelName, completion)
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
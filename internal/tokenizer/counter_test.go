@@ -0,0 +1,61 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tusharr/go-ai-huggingface/internal/model"
+	"github.com/tusharr/go-ai-huggingface/pkg/logger"
+)
+
+func TestCounter(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"org/bert": bertTokenizer,
+		"broken":   `{"model": {"type": "Char"}}`,
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "tokenizer.json"), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	counter := NewCounter(dir, logger.NewNoopLogger())
+
+	tests := []struct {
+		name      string
+		model     string
+		text      string
+		want      int
+		wantInput int
+	}{
+		{name: "tokenizer", model: "org/bert", text: "Unaffable hello!", want: 5, wantInput: 7},
+		{name: "no tokenizer", model: "org/other", text: "Unaffable hello!", want: 4, wantInput: 4},
+		{name: "unusable tokenizer", model: "broken", text: "Unaffable hello!", want: 4, wantInput: 4},
+		{name: "path outside the directory", model: "../" + filepath.Base(dir) + "/org/bert", text: "Unaffable hello!", want: 4, wantInput: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := counter.Count(tt.model, tt.text); got != tt.want {
+				t.Errorf("Count() = %v, want %v", got, tt.want)
+			}
+			if got := counter.CountInput(tt.model, tt.text); got != tt.wantInput {
+				t.Errorf("CountInput() = %v, want %v", got, tt.wantInput)
+			}
+		})
+	}
+
+	usage := counter.Usage("org/bert", "hello!", "unaffable", "hello")
+	want := model.Usage{PromptTokens: 4, CompletionTokens: 4, TotalTokens: 8}
+	if usage != want {
+		t.Errorf("Usage() = %+v, want %+v", usage, want)
+	}
+
+	var unset *Counter
+	if got := unset.Usage("org/bert", "12345678", "1234"); got != (model.Usage{PromptTokens: 2, CompletionTokens: 1, TotalTokens: 3}) {
+		t.Errorf("nil Counter Usage() = %+v, want estimates", got)
+	}
+}
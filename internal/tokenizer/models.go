@@ -0,0 +1,462 @@
+package tokenizer
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseModel builds the tokenization model described by a tokenizer.json
+// entry. Files written by old versions of the library have no model type,
+// which is then inferred from the fields present.
+func parseModel(data json.RawMessage) (tokenModel, error) {
+	if isNull(data) {
+		return nil, fmt.Errorf("missing model")
+	}
+	var spec struct {
+		Type   string          `json:"type"`
+		Vocab  json.RawMessage `json:"vocab"`
+		Merges json.RawMessage `json:"merges"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Type == "" {
+		switch {
+		case spec.Merges != nil:
+			spec.Type = "BPE"
+		case len(spec.Vocab) > 0 && spec.Vocab[0] == '[':
+			spec.Type = "Unigram"
+		default:
+			spec.Type = "WordPiece"
+		}
+	}
+
+	switch spec.Type {
+	case "BPE":
+		return parseBPE(data)
+	case "WordPiece":
+		return parseWordPiece(data)
+	case "Unigram":
+		return parseUnigram(data)
+	}
+	return nil, fmt.Errorf("unsupported type %q", spec.Type)
+}
+
+// bpe merges characters into tokens by applying merge rules in rank order
+type bpe struct {
+	vocab        map[string]int
+	ranks        map[[2]string]int
+	unk          string // replaces unknown characters; they are dropped when empty
+	prefix       string // continuing subword prefix
+	suffix       string // end of word suffix
+	fuseUnk      bool
+	byteFallback bool
+	ignoreMerges bool
+}
+
+func parseBPE(data json.RawMessage) (*bpe, error) {
+	var spec struct {
+		Vocab        map[string]int    `json:"vocab"`
+		Merges       []json.RawMessage `json:"merges"`
+		UnkToken     *string           `json:"unk_token"`
+		Prefix       *string           `json:"continuing_subword_prefix"`
+		Suffix       *string           `json:"end_of_word_suffix"`
+		FuseUnk      bool              `json:"fuse_unk"`
+		ByteFallback bool              `json:"byte_fallback"`
+		IgnoreMerges bool              `json:"ignore_merges"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	m := &bpe{
+		vocab:        spec.Vocab,
+		ranks:        make(map[[2]string]int, len(spec.Merges)),
+		fuseUnk:      spec.FuseUnk,
+		byteFallback: spec.ByteFallback,
+		ignoreMerges: spec.IgnoreMerges,
+	}
+	if spec.UnkToken != nil {
+		m.unk = *spec.UnkToken
+	}
+	if spec.Prefix != nil {
+		m.prefix = *spec.Prefix
+	}
+	if spec.Suffix != nil {
+		m.suffix = *spec.Suffix
+	}
+
+	for rank, raw := range spec.Merges {
+		// Merges are "left right" strings or, since tokenizers 0.20,
+		// ["left", "right"] pairs
+		var pair [2]string
+		var merge string
+		if err := json.Unmarshal(raw, &merge); err == nil {
+			left, right, ok := strings.Cut(merge, " ")
+			if !ok {
+				return nil, fmt.Errorf("invalid merge %q", merge)
+			}
+			pair = [2]string{left, right}
+		} else if err := json.Unmarshal(raw, &pair); err != nil {
+			return nil, fmt.Errorf("invalid merge %s", raw)
+		}
+		if _, ok := m.vocab[m.merge(pair[0], pair[1])]; !ok {
+			return nil, fmt.Errorf("merge %q %q is not in the vocabulary", pair[0], pair[1])
+		}
+		if _, ok := m.ranks[pair]; !ok {
+			m.ranks[pair] = rank
+		}
+	}
+	return m, nil
+}
+
+// merge returns the token made of left followed by right
+func (m *bpe) merge(left, right string) string {
+	if m.prefix != "" {
+		right = strings.TrimPrefix(right, m.prefix)
+	}
+	return left + right
+}
+
+// bpeSymbol is a node of the doubly linked list of symbols of a word
+type bpeSymbol struct {
+	text       string
+	prev, next int
+	removed    bool
+}
+
+// bpeCandidate is a possible merge of the symbol at pos with its successor
+type bpeCandidate struct {
+	rank, pos   int
+	left, right string
+}
+
+// bpeQueue orders merge candidates by rank, then position
+type bpeQueue []bpeCandidate
+
+func (q bpeQueue) Len() int { return len(q) }
+func (q bpeQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].pos < q[j].pos
+}
+func (q bpeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *bpeQueue) Push(x interface{}) { *q = append(*q, x.(bpeCandidate)) }
+func (q *bpeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+func (m *bpe) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	if m.ignoreMerges {
+		if id, ok := m.vocab[word]; ok {
+			return append(ids, id)
+		}
+	}
+
+	symbols := m.symbols(word)
+	queue := &bpeQueue{}
+	push := func(pos int) {
+		next := symbols[pos].next
+		if next < 0 {
+			return
+		}
+		pair := [2]string{symbols[pos].text, symbols[next].text}
+		if rank, ok := m.ranks[pair]; ok {
+			heap.Push(queue, bpeCandidate{rank: rank, pos: pos, left: pair[0], right: pair[1]})
+		}
+	}
+	for i := range symbols {
+		push(i)
+	}
+
+	for queue.Len() > 0 {
+		candidate := heap.Pop(queue).(bpeCandidate)
+		left := &symbols[candidate.pos]
+		if left.removed || left.next < 0 || left.text != candidate.left || symbols[left.next].text != candidate.right {
+			continue // stale: one of the symbols has been merged since
+		}
+		right := &symbols[left.next]
+		left.text = m.merge(left.text, right.text)
+		right.removed = true
+		left.next = right.next
+		if left.next >= 0 {
+			symbols[left.next].prev = candidate.pos
+		}
+		if left.prev >= 0 {
+			push(left.prev)
+		}
+		push(candidate.pos)
+	}
+
+	for i := 0; i >= 0 && i < len(symbols); i = symbols[i].next {
+		if id, ok := m.vocab[symbols[i].text]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// symbols splits word into the initial symbols: its characters, with the
+// subword prefix and end of word suffix applied, and unknown characters
+// replaced by their bytes or the unknown token
+func (m *bpe) symbols(word string) []bpeSymbol {
+	symbols := make([]bpeSymbol, 0, len(word))
+	add := func(text string) {
+		symbols = append(symbols, bpeSymbol{text: text, prev: len(symbols) - 1, next: len(symbols) + 1})
+	}
+	unk := false
+	for i, r := range word {
+		char := string(r)
+		if i > 0 && m.prefix != "" {
+			char = m.prefix + char
+		}
+		if i+len(string(r)) == len(word) && m.suffix != "" {
+			char += m.suffix
+		}
+
+		if _, ok := m.vocab[char]; ok {
+			add(char)
+			unk = false
+			continue
+		}
+		if m.byteFallback {
+			if tokens, ok := m.byteTokens(string(r)); ok {
+				for _, token := range tokens {
+					add(token)
+				}
+				unk = false
+				continue
+			}
+		}
+		if m.unk != "" && !(m.fuseUnk && unk) {
+			add(m.unk)
+			unk = true
+		}
+	}
+	if len(symbols) > 0 {
+		symbols[len(symbols)-1].next = -1
+	}
+	return symbols
+}
+
+// byteTokens returns the <0xXX> tokens of the bytes of s if the vocabulary
+// has all of them
+func (m *bpe) byteTokens(s string) ([]string, bool) {
+	tokens := make([]string, len(s))
+	for i := 0; i < len(s); i++ {
+		tokens[i] = fmt.Sprintf("<0x%02X>", s[i])
+		if _, ok := m.vocab[tokens[i]]; !ok {
+			return nil, false
+		}
+	}
+	return tokens, true
+}
+
+// wordPiece splits words greedily into the longest tokens in the vocabulary
+type wordPiece struct {
+	vocab    map[string]int
+	unkID    int
+	prefix   string
+	maxChars int
+}
+
+func parseWordPiece(data json.RawMessage) (*wordPiece, error) {
+	spec := struct {
+		Vocab    map[string]int `json:"vocab"`
+		UnkToken string         `json:"unk_token"`
+		Prefix   string         `json:"continuing_subword_prefix"`
+		MaxChars int            `json:"max_input_chars_per_word"`
+	}{UnkToken: "[UNK]", Prefix: "##", MaxChars: 100}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	unkID, ok := spec.Vocab[spec.UnkToken]
+	if !ok {
+		return nil, fmt.Errorf("unknown token %q is not in the vocabulary", spec.UnkToken)
+	}
+	return &wordPiece{vocab: spec.Vocab, unkID: unkID, prefix: spec.Prefix, maxChars: spec.MaxChars}, nil
+}
+
+func (m *wordPiece) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	if utf8.RuneCountInString(word) > m.maxChars {
+		return append(ids, m.unkID)
+	}
+
+	n := len(ids)
+	for start := 0; start < len(word); {
+		end := len(word)
+		for end > start {
+			piece := word[start:end]
+			if start > 0 {
+				piece = m.prefix + piece
+			}
+			if id, ok := m.vocab[piece]; ok {
+				ids = append(ids, id)
+				break
+			}
+			_, size := utf8.DecodeLastRuneInString(word[start:end])
+			end -= size
+		}
+		if end == start {
+			// The word cannot be split into known pieces
+			return append(ids[:n], m.unkID)
+		}
+		start = end
+	}
+	return ids
+}
+
+// unigram picks the segmentation of a word with the highest total score
+type unigram struct {
+	pieces       map[string]unigramPiece
+	maxLen       int // longest piece in bytes
+	unkID        int
+	unkScore     float64
+	byteFallback bool
+}
+
+// unigramPiece is a vocabulary entry of a unigram model
+type unigramPiece struct {
+	id    int
+	score float64
+}
+
+// unkPenalty is subtracted from the lowest piece score to score unknown
+// characters, as SentencePiece does
+const unkPenalty = 10.0
+
+func parseUnigram(data json.RawMessage) (*unigram, error) {
+	var spec struct {
+		Vocab        [][2]json.RawMessage `json:"vocab"`
+		UnkID        *int                 `json:"unk_id"`
+		ByteFallback bool                 `json:"byte_fallback"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	m := &unigram{
+		pieces:       make(map[string]unigramPiece, len(spec.Vocab)),
+		byteFallback: spec.ByteFallback,
+		unkID:        -1,
+	}
+	minScore := math.Inf(1)
+	for id, entry := range spec.Vocab {
+		var piece unigramPiece
+		var text string
+		if err := json.Unmarshal(entry[0], &text); err != nil {
+			return nil, fmt.Errorf("invalid vocabulary entry %d: %w", id, err)
+		}
+		if err := json.Unmarshal(entry[1], &piece.score); err != nil {
+			return nil, fmt.Errorf("invalid vocabulary entry %d: %w", id, err)
+		}
+		piece.id = id
+		m.pieces[text] = piece
+		m.maxLen = max(m.maxLen, len(text))
+		minScore = math.Min(minScore, piece.score)
+	}
+	if spec.UnkID != nil {
+		m.unkID = *spec.UnkID
+	}
+	m.unkScore = minScore - unkPenalty
+	return m, nil
+}
+
+func (m *unigram) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+
+	// best[i] is the best segmentation of word[:i], ending with the piece
+	// word[best[i].start:i]
+	type node struct {
+		score     float64
+		start, id int
+		reached   bool
+	}
+	best := make([]node, len(word)+1)
+	best[0].reached = true
+	for start := 0; start < len(word); {
+		_, size := utf8.DecodeRuneInString(word[start:])
+		if best[start].reached {
+			single := false
+			for end := start + 1; end <= len(word) && end-start <= m.maxLen; end++ {
+				if end < len(word) && !utf8.RuneStart(word[end]) {
+					continue
+				}
+				piece, ok := m.pieces[word[start:end]]
+				if !ok {
+					continue
+				}
+				single = single || end == start+size
+				score := best[start].score + piece.score
+				if !best[end].reached || score > best[end].score {
+					best[end] = node{score: score, start: start, id: piece.id, reached: true}
+				}
+			}
+			if !single {
+				end := start + size
+				score := best[start].score + m.unkScore
+				if !best[end].reached || score > best[end].score {
+					best[end] = node{score: score, start: start, id: -1, reached: true}
+				}
+			}
+		}
+		start += size
+	}
+
+	// Walk back from the end, then emit the pieces in order
+	var path []int
+	for end := len(word); end > 0; end = best[end].start {
+		path = append(path, end)
+	}
+	unk := false
+	for i := len(path) - 1; i >= 0; i-- {
+		end := path[i]
+		if best[end].id >= 0 {
+			ids = append(ids, best[end].id)
+			unk = false
+			continue
+		}
+		if m.byteFallback {
+			if byteIDs, ok := m.byteIDs(word[best[end].start:end]); ok {
+				ids = append(ids, byteIDs...)
+				unk = false
+				continue
+			}
+		}
+		if !unk && m.unkID >= 0 {
+			ids = append(ids, m.unkID)
+		}
+		unk = true
+	}
+	return ids
+}
+
+// byteIDs returns the IDs of the <0xXX> pieces of the bytes of s if the
+// vocabulary has all of them
+func (m *unigram) byteIDs(s string) ([]int, bool) {
+	ids := make([]int, len(s))
+	for i := 0; i < len(s); i++ {
+		piece, ok := m.pieces[fmt.Sprintf("<0x%02X>", s[i])]
+		if !ok {
+			return nil, false
+		}
+		ids[i] = piece.id
+	}
+	return ids, true
+}
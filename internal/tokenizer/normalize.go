@@ -0,0 +1,163 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// normalizer transforms text before pre-tokenization
+type normalizer func(string) string
+
+// pattern is a literal string or a regular expression in tokenizer.json
+type pattern struct {
+	String *string `json:"String"`
+	Regex  *string `json:"Regex"`
+}
+
+// expr returns the pattern as a regular expression
+func (p pattern) expr() (string, error) {
+	switch {
+	case p.String != nil:
+		return regexp.QuoteMeta(*p.String), nil
+	case p.Regex != nil:
+		return *p.Regex, nil
+	}
+	return "", fmt.Errorf("pattern must be a String or a Regex")
+}
+
+// parseNormalizer builds the normalizer described by a tokenizer.json entry
+func parseNormalizer(data json.RawMessage) (normalizer, error) {
+	if isNull(data) {
+		return nil, nil
+	}
+	var spec struct {
+		Type               string            `json:"type"`
+		Normalizers        []json.RawMessage `json:"normalizers"`
+		CleanText          bool              `json:"clean_text"`
+		HandleChineseChars bool              `json:"handle_chinese_chars"`
+		Lowercase          bool              `json:"lowercase"`
+		StripLeft          bool              `json:"strip_left"`
+		StripRight         bool              `json:"strip_right"`
+		Prepend            string            `json:"prepend"`
+		Pattern            pattern           `json:"pattern"`
+		Content            string            `json:"content"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	switch spec.Type {
+	case "Sequence":
+		normalizers := make([]normalizer, 0, len(spec.Normalizers))
+		for _, raw := range spec.Normalizers {
+			n, err := parseNormalizer(raw)
+			if err != nil {
+				return nil, err
+			}
+			if n != nil {
+				normalizers = append(normalizers, n)
+			}
+		}
+		return func(s string) string {
+			for _, n := range normalizers {
+				s = n(s)
+			}
+			return s
+		}, nil
+	case "BertNormalizer":
+		return bertNormalizer(spec.CleanText, spec.HandleChineseChars, spec.Lowercase), nil
+	case "Lowercase":
+		return strings.ToLower, nil
+	case "Strip":
+		return func(s string) string {
+			if spec.StripLeft {
+				s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			}
+			if spec.StripRight {
+				s = strings.TrimRightFunc(s, unicode.IsSpace)
+			}
+			return s
+		}, nil
+	case "Prepend":
+		return func(s string) string {
+			if s == "" {
+				return s
+			}
+			return spec.Prepend + s
+		}, nil
+	case "Replace":
+		if spec.Pattern.String != nil {
+			old := *spec.Pattern.String
+			return func(s string) string {
+				return strings.ReplaceAll(s, old, spec.Content)
+			}, nil
+		}
+		expr, err := spec.Pattern.expr()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(s string) string {
+			return re.ReplaceAllLiteralString(s, spec.Content)
+		}, nil
+	case "NFC", "NFD", "NFKC", "NFKD", "StripAccents", "Precompiled", "Nmt":
+		// See the package documentation
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", spec.Type)
+}
+
+// bertNormalizer cleans text the way BERT tokenizers do: control characters
+// are dropped, whitespace becomes a space and CJK ideographs are surrounded
+// by spaces so that each becomes a word
+func bertNormalizer(cleanText, chineseChars, lowercase bool) normalizer {
+	return func(s string) string {
+		var b strings.Builder
+		b.Grow(len(s))
+		for _, r := range s {
+			if cleanText {
+				if r == 0 || r == unicode.ReplacementChar || isControl(r) {
+					continue
+				}
+				if r == '\t' || r == '\n' || r == '\r' || unicode.Is(unicode.Zs, r) {
+					r = ' '
+				}
+			}
+			if chineseChars && isChinese(r) {
+				b.WriteByte(' ')
+				b.WriteRune(r)
+				b.WriteByte(' ')
+				continue
+			}
+			b.WriteRune(r)
+		}
+		if lowercase {
+			return strings.ToLower(b.String())
+		}
+		return b.String()
+	}
+}
+
+func isControl(r rune) bool {
+	if r == '\t' || r == '\n' || r == '\r' {
+		return false
+	}
+	return unicode.In(r, unicode.Cc, unicode.Cf, unicode.Co)
+}
+
+func isChinese(r rune) bool {
+	return (r >= 0x4E00 && r <= 0x9FFF) ||
+		(r >= 0x3400 && r <= 0x4DBF) ||
+		(r >= 0x20000 && r <= 0x2A6DF) ||
+		(r >= 0x2A700 && r <= 0x2B73F) ||
+		(r >= 0x2B740 && r <= 0x2B81F) ||
+		(r >= 0x2B820 && r <= 0x2CEAF) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0x2F800 && r <= 0x2FA1F)
+}
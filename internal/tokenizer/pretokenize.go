@@ -0,0 +1,421 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// preTokenizer splits normalized text into words. first reports whether the
+// text starts the input rather than following an added token.
+type preTokenizer func(pieces []string, first bool) []string
+
+// Split behaviors: what happens to the delimiters a pre-tokenizer matches
+const (
+	behaviorRemoved            = "Removed"
+	behaviorIsolated           = "Isolated"
+	behaviorMergedWithPrevious = "MergedWithPrevious"
+	behaviorMergedWithNext     = "MergedWithNext"
+	behaviorContiguous         = "Contiguous"
+)
+
+// gpt2Pattern is the word pattern of byte-level BPE tokenizers
+const gpt2Pattern = `'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+(?!\S)|\s+`
+
+// trailingSpace is the lookahead used by GPT-style patterns, which Go's
+// regexp package does not support. It is rewritten to a named group and
+// emulated by splitPattern.
+const trailingSpace = `\s+(?!\S)`
+
+// parsePreTokenizer builds the pre-tokenizer described by a tokenizer.json
+// entry
+func parsePreTokenizer(data json.RawMessage) (preTokenizer, error) {
+	if isNull(data) {
+		return nil, nil
+	}
+	var spec struct {
+		Type             string            `json:"type"`
+		PreTokenizers    []json.RawMessage `json:"pretokenizers"`
+		AddPrefixSpace   bool              `json:"add_prefix_space"`
+		UseRegex         *bool             `json:"use_regex"`
+		Replacement      string            `json:"replacement"`
+		PrependScheme    string            `json:"prepend_scheme"`
+		Split            *bool             `json:"split"`
+		Pattern          pattern           `json:"pattern"`
+		Behavior         string            `json:"behavior"`
+		Invert           bool              `json:"invert"`
+		IndividualDigits bool              `json:"individual_digits"`
+		Delimiter        string            `json:"delimiter"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	switch spec.Type {
+	case "Sequence":
+		steps := make([]preTokenizer, 0, len(spec.PreTokenizers))
+		for _, raw := range spec.PreTokenizers {
+			step, err := parsePreTokenizer(raw)
+			if err != nil {
+				return nil, err
+			}
+			if step != nil {
+				steps = append(steps, step)
+			}
+		}
+		return func(pieces []string, first bool) []string {
+			for _, step := range steps {
+				pieces = step(pieces, first)
+			}
+			return pieces
+		}, nil
+	case "ByteLevel":
+		var words *splitPattern
+		if spec.UseRegex == nil || *spec.UseRegex {
+			words = mustCompilePattern(gpt2Pattern)
+		}
+		return byteLevel(spec.AddPrefixSpace, words), nil
+	case "Whitespace":
+		words := mustCompilePattern(`[\p{L}\p{M}\p{N}_]+|[^\p{L}\p{M}\p{N}_\s]+`)
+		return splitWith(func(s string) [][2]int {
+			return words.find(s)
+		}, behaviorRemoved, true), nil
+	case "WhitespaceSplit":
+		return func(pieces []string, _ bool) []string {
+			var out []string
+			for _, piece := range pieces {
+				out = append(out, strings.Fields(piece)...)
+			}
+			return out
+		}, nil
+	case "BertPreTokenizer":
+		return func(pieces []string, _ bool) []string {
+			var out []string
+			for _, piece := range pieces {
+				for _, word := range strings.Fields(piece) {
+					out = append(out, splitSpans(word, runeSpans(word, isPunctuation, false), behaviorIsolated)...)
+				}
+			}
+			return out
+		}, nil
+	case "Punctuation":
+		return splitWith(func(s string) [][2]int {
+			return runeSpans(s, isPunctuation, false)
+		}, behaviorOr(spec.Behavior, behaviorIsolated), false), nil
+	case "Digits":
+		return splitWith(func(s string) [][2]int {
+			return runeSpans(s, unicode.IsNumber, !spec.IndividualDigits)
+		}, behaviorIsolated, false), nil
+	case "CharDelimiterSplit":
+		delimiter, _ := utf8.DecodeRuneInString(spec.Delimiter)
+		return splitWith(func(s string) [][2]int {
+			return runeSpans(s, func(r rune) bool { return r == delimiter }, false)
+		}, behaviorRemoved, false), nil
+	case "Split":
+		expr, err := spec.Pattern.expr()
+		if err != nil {
+			return nil, err
+		}
+		delimiters, err := compilePattern(expr)
+		if err != nil {
+			return nil, err
+		}
+		return splitWith(delimiters.find, behaviorOr(spec.Behavior, behaviorRemoved), spec.Invert), nil
+	case "Metaspace":
+		scheme := spec.PrependScheme
+		if scheme == "" {
+			scheme = "never"
+			if spec.AddPrefixSpace {
+				scheme = "always"
+			}
+		}
+		replacement := spec.Replacement
+		if replacement == "" {
+			replacement = "▁"
+		}
+		return metaspace(replacement, scheme, spec.Split == nil || *spec.Split), nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", spec.Type)
+}
+
+func behaviorOr(behavior, fallback string) string {
+	if behavior == "" {
+		return fallback
+	}
+	return behavior
+}
+
+// splitWith returns a pre-tokenizer splitting every piece at the spans
+// returned by find. With invert the spans are the words and the text between
+// them the delimiters.
+func splitWith(find func(string) [][2]int, behavior string, invert bool) preTokenizer {
+	return func(pieces []string, _ bool) []string {
+		var out []string
+		for _, piece := range pieces {
+			spans := find(piece)
+			if invert {
+				spans = complement(spans, len(piece))
+			}
+			out = append(out, splitSpans(piece, spans, behavior)...)
+		}
+		return out
+	}
+}
+
+// byteLevel splits text into GPT-2 style words and maps their bytes to
+// printable characters
+func byteLevel(addPrefixSpace bool, words *splitPattern) preTokenizer {
+	return func(pieces []string, _ bool) []string {
+		var out []string
+		for _, piece := range pieces {
+			if addPrefixSpace && !strings.HasPrefix(piece, " ") {
+				piece = " " + piece
+			}
+			split := []string{piece}
+			if words != nil {
+				split = splitSpans(piece, words.find(piece), behaviorIsolated)
+			}
+			for _, word := range split {
+				out = append(out, byteLevelEncode(word))
+			}
+		}
+		return out
+	}
+}
+
+// metaspace replaces spaces with replacement, optionally prepends it and
+// splits the text into words starting with it
+func metaspace(replacement, scheme string, split bool) preTokenizer {
+	return func(pieces []string, first bool) []string {
+		var out []string
+		for i, piece := range pieces {
+			piece = strings.ReplaceAll(piece, " ", replacement)
+			prepend := scheme == "always" || (scheme == "first" && first && i == 0)
+			if prepend && !strings.HasPrefix(piece, replacement) {
+				piece = replacement + piece
+			}
+			if !split {
+				out = append(out, piece)
+				continue
+			}
+			var spans [][2]int
+			for offset := 0; ; {
+				at := strings.Index(piece[offset:], replacement)
+				if at < 0 {
+					break
+				}
+				spans = append(spans, [2]int{offset + at, offset + at + len(replacement)})
+				offset += at + len(replacement)
+			}
+			out = append(out, splitSpans(piece, spans, behaviorMergedWithNext)...)
+		}
+		return out
+	}
+}
+
+// splitPattern finds delimiters with a regular expression
+type splitPattern struct {
+	re *regexp.Regexp
+	// trailing is the submatch index of the rewritten trailingSpace group,
+	// or 0 when the pattern has none
+	trailing int
+}
+
+// compilePattern compiles a tokenizer.json regular expression
+func compilePattern(expr string) (*splitPattern, error) {
+	rewritten := strings.ReplaceAll(expr, trailingSpace, `(?P<trailing>\s+)`)
+	re, err := regexp.Compile(rewritten)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported pattern %q: %w", expr, err)
+	}
+	p := &splitPattern{re: re}
+	if rewritten != expr {
+		p.trailing = re.SubexpIndex("trailing")
+	}
+	return p, nil
+}
+
+func mustCompilePattern(expr string) *splitPattern {
+	p, err := compilePattern(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// find returns the spans of s matched by the pattern. A whitespace run
+// matched by the trailingSpace group and followed by a non-space character
+// gives up its last character, which is what the lookahead does.
+func (p *splitPattern) find(s string) [][2]int {
+	var spans [][2]int
+	for offset := 0; offset < len(s); {
+		loc := p.re.FindStringSubmatchIndex(s[offset:])
+		if loc == nil {
+			break
+		}
+		start, end := offset+loc[0], offset+loc[1]
+		if end == start {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			offset = end + size
+			continue
+		}
+		if p.trailing > 0 && loc[2*p.trailing] == loc[0] && loc[2*p.trailing+1] == loc[1] && end < len(s) && !isRegexpSpace(s[end]) {
+			_, size := utf8.DecodeLastRuneInString(s[start:end])
+			if end-size > start {
+				end -= size
+			}
+		}
+		spans = append(spans, [2]int{start, end})
+		offset = end
+	}
+	return spans
+}
+
+// isRegexpSpace matches the characters of \s in Go regular expressions
+func isRegexpSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\f' || b == '\r'
+}
+
+// runeSpans returns the spans of runes matching match, merging adjacent
+// runes when contiguous is set
+func runeSpans(s string, match func(rune) bool, contiguous bool) [][2]int {
+	var spans [][2]int
+	for i, r := range s {
+		if !match(r) {
+			continue
+		}
+		end := i + utf8.RuneLen(r)
+		if contiguous && len(spans) > 0 && spans[len(spans)-1][1] == i {
+			spans[len(spans)-1][1] = end
+			continue
+		}
+		spans = append(spans, [2]int{i, end})
+	}
+	return spans
+}
+
+// complement returns the spans of a string of length n not covered by spans
+func complement(spans [][2]int, n int) [][2]int {
+	var out [][2]int
+	start := 0
+	for _, span := range spans {
+		if span[0] > start {
+			out = append(out, [2]int{start, span[0]})
+		}
+		start = span[1]
+	}
+	if start < n {
+		out = append(out, [2]int{start, n})
+	}
+	return out
+}
+
+// splitSpans splits s at the delimiter spans, handling the delimiters
+// according to behavior. Empty pieces are dropped.
+func splitSpans(s string, spans [][2]int, behavior string) []string {
+	type segment struct {
+		start, end int
+		delimiter  bool
+	}
+	segments := make([]segment, 0, 2*len(spans)+1)
+	start := 0
+	for _, span := range spans {
+		if span[0] > start {
+			segments = append(segments, segment{start, span[0], false})
+		}
+		segments = append(segments, segment{span[0], span[1], true})
+		start = span[1]
+	}
+	if start < len(s) {
+		segments = append(segments, segment{start, len(s), false})
+	}
+
+	var merged []segment
+	switch behavior {
+	case behaviorRemoved:
+		for _, seg := range segments {
+			if !seg.delimiter {
+				merged = append(merged, seg)
+			}
+		}
+	case behaviorMergedWithPrevious:
+		previous := false
+		for _, seg := range segments {
+			if seg.delimiter && !previous && len(merged) > 0 {
+				merged[len(merged)-1].end = seg.end
+			} else {
+				merged = append(merged, seg)
+			}
+			previous = seg.delimiter
+		}
+	case behaviorMergedWithNext:
+		next := false
+		for i := len(segments) - 1; i >= 0; i-- {
+			seg := segments[i]
+			if seg.delimiter && !next && len(merged) > 0 {
+				merged[len(merged)-1].start = seg.start
+			} else {
+				merged = append(merged, seg)
+			}
+			next = seg.delimiter
+		}
+		for i, j := 0, len(merged)-1; i < j; i, j = i+1, j-1 {
+			merged[i], merged[j] = merged[j], merged[i]
+		}
+	case behaviorContiguous:
+		for _, seg := range segments {
+			if n := len(merged); seg.delimiter && n > 0 && merged[n-1].delimiter {
+				merged[n-1].end = seg.end
+				continue
+			}
+			merged = append(merged, seg)
+		}
+	default:
+		merged = segments
+	}
+
+	out := make([]string, 0, len(merged))
+	for _, seg := range merged {
+		if seg.end > seg.start {
+			out = append(out, s[seg.start:seg.end])
+		}
+	}
+	return out
+}
+
+// isPunctuation matches ASCII symbols and Unicode punctuation, which BERT
+// style tokenizers split into words of their own
+func isPunctuation(r rune) bool {
+	if (r >= 33 && r <= 47) || (r >= 58 && r <= 64) || (r >= 91 && r <= 96) || (r >= 123 && r <= 126) {
+		return true
+	}
+	return unicode.IsPunct(r)
+}
+
+// byteLevelAlphabet maps every byte to a printable character, as GPT-2 does
+var byteLevelAlphabet = func() [256]rune {
+	var alphabet [256]rune
+	next := rune(256)
+	for b := 0; b < 256; b++ {
+		if (b >= '!' && b <= '~') || (b >= 0xA1 && b <= 0xAC) || (b >= 0xAE && b <= 0xFF) {
+			alphabet[b] = rune(b)
+		} else {
+			alphabet[b] = next
+			next++
+		}
+	}
+	return alphabet
+}()
+
+// byteLevelEncode maps the bytes of s to the byte-level alphabet
+func byteLevelEncode(s string) string {
+	var b strings.Builder
+	b.Grow(2 * len(s))
+	for i := 0; i < len(s); i++ {
+		b.WriteRune(byteLevelAlphabet[s[i]])
+	}
+	return b.String()
+}
@@ -0,0 +1,224 @@
+// Package tokenizer counts tokens with Hugging Face tokenizer.json files. It
+// implements the BPE, WordPiece and Unigram models together with the common
+// normalizers, pre-tokenizers and post-processors. Unicode normalization
+// forms (NFC, NFKC, ...), precompiled SentencePiece normalizers and accent
+// stripping are not applied, so counts can differ slightly for text that is
+// not already normalized.
+package tokenizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer encodes text into token IDs as described by a tokenizer.json file
+type Tokenizer struct {
+	added       map[byte][]addedToken // by first byte, longest content first
+	normalize   normalizer
+	preTokenize preTokenizer
+	model       tokenModel
+	specials    int // tokens the post-processor adds around a single sequence
+}
+
+// addedToken is a token matched verbatim in the input before normalization
+type addedToken struct {
+	ID      int    `json:"id"`
+	Content string `json:"content"`
+	LStrip  bool   `json:"lstrip"`
+	RStrip  bool   `json:"rstrip"`
+}
+
+// tokenizerFile is the subset of tokenizer.json used for encoding
+type tokenizerFile struct {
+	AddedTokens   []addedToken    `json:"added_tokens"`
+	Normalizer    json.RawMessage `json:"normalizer"`
+	PreTokenizer  json.RawMessage `json:"pre_tokenizer"`
+	PostProcessor json.RawMessage `json:"post_processor"`
+	Model         json.RawMessage `json:"model"`
+}
+
+// tokenModel splits a pre-tokenized word into tokens
+type tokenModel interface {
+	tokenize(word string, ids []int) []int
+}
+
+// Load reads a tokenizer.json file
+func Load(path string) (*Tokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse builds a tokenizer from the contents of a tokenizer.json file
+func Parse(data []byte) (*Tokenizer, error) {
+	var file tokenizerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid tokenizer file: %w", err)
+	}
+
+	t := &Tokenizer{added: make(map[byte][]addedToken)}
+	var err error
+	if t.normalize, err = parseNormalizer(file.Normalizer); err != nil {
+		return nil, fmt.Errorf("normalizer: %w", err)
+	}
+	if t.preTokenize, err = parsePreTokenizer(file.PreTokenizer); err != nil {
+		return nil, fmt.Errorf("pre_tokenizer: %w", err)
+	}
+	if t.specials, err = parsePostProcessor(file.PostProcessor); err != nil {
+		return nil, fmt.Errorf("post_processor: %w", err)
+	}
+	if t.model, err = parseModel(file.Model); err != nil {
+		return nil, fmt.Errorf("model: %w", err)
+	}
+
+	for _, token := range file.AddedTokens {
+		if token.Content != "" {
+			t.added[token.Content[0]] = append(t.added[token.Content[0]], token)
+		}
+	}
+	for _, tokens := range t.added {
+		sort.SliceStable(tokens, func(i, j int) bool {
+			return len(tokens[i].Content) > len(tokens[j].Content)
+		})
+	}
+	return t, nil
+}
+
+// Encode returns the token IDs of text, without the special tokens added by
+// the post-processor
+func (t *Tokenizer) Encode(text string) []int {
+	var ids []int
+	first := true
+	start := 0
+	for i := 0; i < len(text); i++ {
+		token, ok := t.addedAt(text, i)
+		if !ok {
+			continue
+		}
+		segment := text[start:i]
+		if token.LStrip {
+			segment = strings.TrimRightFunc(segment, unicode.IsSpace)
+		}
+		ids = t.encodeSegment(segment, first, ids)
+		ids = append(ids, token.ID)
+		first = false
+
+		i += len(token.Content)
+		if token.RStrip {
+			i += len(text[i:]) - len(strings.TrimLeftFunc(text[i:], unicode.IsSpace))
+		}
+		start = i
+		i--
+	}
+	return t.encodeSegment(text[start:], first, ids)
+}
+
+// Count returns the number of tokens of text, without special tokens
+func (t *Tokenizer) Count(text string) int {
+	return len(t.Encode(text))
+}
+
+// SpecialTokens returns the number of special tokens the tokenizer adds
+// around a single input sequence, such as [CLS] and [SEP] or <s>
+func (t *Tokenizer) SpecialTokens() int {
+	return t.specials
+}
+
+// addedAt returns the longest added token starting at text[i:]
+func (t *Tokenizer) addedAt(text string, i int) (addedToken, bool) {
+	for _, token := range t.added[text[i]] {
+		if strings.HasPrefix(text[i:], token.Content) {
+			return token, true
+		}
+	}
+	return addedToken{}, false
+}
+
+// encodeSegment encodes text between added tokens
+func (t *Tokenizer) encodeSegment(text string, first bool, ids []int) []int {
+	if text == "" {
+		return ids
+	}
+	if t.normalize != nil {
+		text = t.normalize(text)
+	}
+	words := []string{text}
+	if t.preTokenize != nil {
+		words = t.preTokenize(words, first)
+	}
+	for _, word := range words {
+		ids = t.model.tokenize(word, ids)
+	}
+	return ids
+}
+
+// parsePostProcessor returns the number of special tokens a post-processor
+// adds around a single sequence
+func parsePostProcessor(data json.RawMessage) (int, error) {
+	if isNull(data) {
+		return 0, nil
+	}
+	var spec struct {
+		Type          string            `json:"type"`
+		Processors    []json.RawMessage `json:"processors"`
+		Single        []json.RawMessage `json:"single"`
+		SpecialTokens map[string]struct {
+			IDs []int `json:"ids"`
+		} `json:"special_tokens"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return 0, err
+	}
+
+	switch spec.Type {
+	case "ByteLevel":
+		return 0, nil
+	case "BertProcessing", "RobertaProcessing":
+		return 2, nil
+	case "TemplateProcessing":
+		count := 0
+		for _, piece := range spec.Single {
+			var item struct {
+				SpecialToken *struct {
+					ID string `json:"id"`
+				} `json:"SpecialToken"`
+			}
+			if err := json.Unmarshal(piece, &item); err != nil {
+				return 0, err
+			}
+			if item.SpecialToken == nil {
+				continue
+			}
+			if special, ok := spec.SpecialTokens[item.SpecialToken.ID]; ok {
+				count += len(special.IDs)
+			} else {
+				count++
+			}
+		}
+		return count, nil
+	case "Sequence":
+		count := 0
+		for _, processor := range spec.Processors {
+			n, err := parsePostProcessor(processor)
+			if err != nil {
+				return 0, err
+			}
+			count += n
+		}
+		return count, nil
+	}
+	return 0, fmt.Errorf("unsupported type %q", spec.Type)
+}
+
+// isNull reports whether a JSON value is absent or null
+func isNull(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(data, []byte("null"))
+}
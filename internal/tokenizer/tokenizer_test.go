@@ -0,0 +1,148 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+const gpt2Tokenizer = `{
+	"added_tokens": [{"id": 17, "content": "<|endoftext|>", "special": true}],
+	"normalizer": null,
+	"pre_tokenizer": {"type": "ByteLevel", "add_prefix_space": false, "trim_offsets": true, "use_regex": true},
+	"post_processor": {"type": "ByteLevel", "add_prefix_space": true, "trim_offsets": false},
+	"model": {
+		"type": "BPE",
+		"vocab": {"h": 0, "e": 1, "l": 2, "o": 3, "Ġ": 4, "w": 5, "r": 6, "d": 7, "he": 8, "ll": 9, "hell": 10,
+			"hello": 11, "Ġw": 12, "or": 13, "Ġwor": 14, "Ġworl": 15, "Ġworld": 16, "<|endoftext|>": 17},
+		"merges": ["h e", "l l", "he ll", "hell o", "Ġ w", "o r", "Ġw or", "Ġwor l", ["Ġworl", "d"]]
+	}
+}`
+
+const bertTokenizer = `{
+	"normalizer": {"type": "BertNormalizer", "clean_text": true, "handle_chinese_chars": true, "strip_accents": null, "lowercase": true},
+	"pre_tokenizer": {"type": "BertPreTokenizer"},
+	"post_processor": {"type": "BertProcessing", "sep": ["[SEP]", 2], "cls": ["[CLS]", 1]},
+	"model": {
+		"type": "WordPiece",
+		"unk_token": "[UNK]",
+		"continuing_subword_prefix": "##",
+		"max_input_chars_per_word": 100,
+		"vocab": {"[UNK]": 0, "[CLS]": 1, "[SEP]": 2, "un": 3, "##aff": 4, "##able": 5, "hello": 6, "!": 7, "中": 8}
+	}
+}`
+
+const unigramTokenizer = `{
+	"pre_tokenizer": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "always", "split": true},
+	"post_processor": {
+		"type": "TemplateProcessing",
+		"single": [{"Sequence": {"id": "A", "type_id": 0}}, {"SpecialToken": {"id": "</s>", "type_id": 0}}],
+		"special_tokens": {"</s>": {"id": "</s>", "ids": [9], "tokens": ["</s>"]}}
+	},
+	"model": {
+		"type": "Unigram",
+		"unk_id": 0,
+		"vocab": [["<unk>", 0.0], ["▁", -2.0], ["▁hello", -1.0], ["▁world", -1.5], ["▁wor", -3.0], ["ld", -3.0], ["he", -4.0], ["llo", -4.0]]
+	}
+}`
+
+const llamaTokenizer = `{
+	"normalizer": {"type": "Sequence", "normalizers": [
+		{"type": "Prepend", "prepend": "▁"},
+		{"type": "Replace", "pattern": {"String": " "}, "content": "▁"}
+	]},
+	"pre_tokenizer": null,
+	"post_processor": {
+		"type": "TemplateProcessing",
+		"single": [{"SpecialToken": {"id": "<s>", "type_id": 0}}, {"Sequence": {"id": "A", "type_id": 0}}],
+		"special_tokens": {"<s>": {"id": "<s>", "ids": [1], "tokens": ["<s>"]}}
+	},
+	"model": {
+		"type": "BPE",
+		"unk_token": "<unk>",
+		"fuse_unk": true,
+		"byte_fallback": true,
+		"vocab": {"<unk>": 0, "<s>": 1, "<0xE2>": 2, "<0x98>": 3, "<0x83>": 4, "▁": 5, "h": 6, "i": 7, "▁h": 8, "▁hi": 9},
+		"merges": ["▁ h", "▁h i"]
+	}
+}`
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		text         string
+		want         []int
+		wantSpecials int
+	}{
+		{name: "byte-level BPE", file: gpt2Tokenizer, text: "hello world", want: []int{11, 16}},
+		{name: "byte-level BPE trailing space", file: gpt2Tokenizer, text: "hello  world", want: []int{11, 4, 16}},
+		{name: "byte-level BPE partial merges", file: gpt2Tokenizer, text: "held", want: []int{8, 2, 7}},
+		{name: "added token", file: gpt2Tokenizer, text: "hello<|endoftext|>world", want: []int{11, 17, 5, 13, 2, 7}},
+		{name: "WordPiece", file: bertTokenizer, text: "Unaffable hello!", want: []int{3, 4, 5, 6, 7}, wantSpecials: 2},
+		{name: "WordPiece unknown word", file: bertTokenizer, text: "hello xyz中", want: []int{6, 0, 8}, wantSpecials: 2},
+		{name: "Unigram", file: unigramTokenizer, text: "hello world", want: []int{2, 3}, wantSpecials: 1},
+		{name: "Unigram unknown characters", file: unigramTokenizer, text: "hello ☃☃", want: []int{2, 1, 0}, wantSpecials: 1},
+		{name: "BPE byte fallback", file: llamaTokenizer, text: "hi ☃", want: []int{9, 5, 2, 3, 4}, wantSpecials: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenizer, err := Parse([]byte(tt.file))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if got := tokenizer.Encode(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encode(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			if got := tokenizer.SpecialTokens(); got != tt.wantSpecials {
+				t.Errorf("SpecialTokens() = %v, want %v", got, tt.wantSpecials)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{name: "invalid JSON", file: `{`},
+		{name: "missing model", file: `{"model": null}`},
+		{name: "unknown model", file: `{"model": {"type": "Char"}}`},
+		{name: "unknown pre-tokenizer", file: `{"pre_tokenizer": {"type": "UnicodeScripts"}, "model": {"type": "WordPiece", "vocab": {"[UNK]": 0}}}`},
+		{name: "merge outside vocabulary", file: `{"model": {"type": "BPE", "vocab": {"a": 0, "b": 1}, "merges": ["a b"]}}`},
+		{name: "WordPiece without unknown token", file: `{"model": {"type": "WordPiece", "vocab": {"a": 0}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.file)); err == nil {
+				t.Errorf("Parse() expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestSplitSpans(t *testing.T) {
+	text := "a--b-c"
+	spans := [][2]int{{1, 2}, {2, 3}, {4, 5}}
+
+	tests := []struct {
+		behavior string
+		want     []string
+	}{
+		{behavior: behaviorRemoved, want: []string{"a", "b", "c"}},
+		{behavior: behaviorIsolated, want: []string{"a", "-", "-", "b", "-", "c"}},
+		{behavior: behaviorMergedWithPrevious, want: []string{"a-", "-", "b-", "c"}},
+		{behavior: behaviorMergedWithNext, want: []string{"a", "-", "-b", "-c"}},
+		{behavior: behaviorContiguous, want: []string{"a", "--", "b", "-", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			if got := splitSpans(text, spans, tt.behavior); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitSpans() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
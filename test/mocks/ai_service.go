@@ -12,15 +12,27 @@ import (
 type MockAIService struct {
 	GenerateTextFunc       func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error)
 	GenerateCompletionFunc func(ctx context.Context, req *model.AIRequest) (*model.AIResponse, error)
-	AnalyzeSentimentFunc   func(ctx context.Context, text string) (*model.SentimentResponse, error)
-	SummarizeTextFunc      func(ctx context.Context, text string, maxLength int) (*model.SummaryResponse, error)
-	ValidateModelFunc      func(model string) error
+	GenerateTextStreamFunc func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error)
+	AnalyzeSentimentFunc   func(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error)
+	SummarizeTextFunc      func(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error)
+	EmbedFunc              func(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error)
+	ClassifyZeroShotFunc   func(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error)
+	ExtractEntitiesFunc    func(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error)
+	TranslateFunc          func(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error)
+	AnswerQuestionFunc     func(ctx context.Context, question, contextText string) (*model.QAResponse, error)
+	ValidateModelFunc      func(ctx context.Context, modelName string) (*model.ModelInfo, error)
 
 	// Call tracking
 	GenerateTextCalls       int
 	GenerateCompletionCalls int
+	GenerateTextStreamCalls int
 	AnalyzeSentimentCalls   int
 	SummarizeTextCalls      int
+	EmbedCalls              int
+	ClassifyZeroShotCalls   int
+	ExtractEntitiesCalls    int
+	TranslateCalls          int
+	AnswerQuestionCalls     int
 	ValidateModelCalls      int
 }
 
@@ -67,39 +79,140 @@ func NewMockAIService() *MockAIService {
 				},
 			}, nil
 		},
-		AnalyzeSentimentFunc: func(ctx context.Context, text string) (*model.SentimentResponse, error) {
+		GenerateTextStreamFunc: func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+			tokens := []string{"Generated", " text", " for: ", req.Prompt}
+			for i, token := range tokens {
+				if err := fn(&model.StreamChunk{ID: req.ID, Model: req.Model, Index: i, Text: token}); err != nil {
+					return nil, err
+				}
+			}
+			return &model.AIResponse{
+				ID:           req.ID,
+				Model:        req.Model,
+				GeneratedAt:  time.Now(),
+				ProcessingMs: 100,
+				Choices: []model.Choice{
+					{
+						Index:        0,
+						Text:         "Generated text for: " + req.Prompt,
+						FinishReason: "stop",
+					},
+				},
+				Usage: model.Usage{
+					PromptTokens:     len(req.Prompt) / 4,
+					CompletionTokens: len(tokens),
+					TotalTokens:      len(req.Prompt)/4 + len(tokens),
+				},
+			}, nil
+		},
+		AnalyzeSentimentFunc: func(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
 			sentiment := "positive"
 			score := 0.8
-			if len(text) < 10 {
+			if len(req.Text) < 10 {
 				sentiment = "negative"
 				score = 0.3
 			}
 			return &model.SentimentResponse{
-				Text:       text,
+				Text:       req.Text,
 				Sentiment:  sentiment,
 				Score:      score,
 				Confidence: score,
+				Model:      req.Model,
 			}, nil
 		},
-		SummarizeTextFunc: func(ctx context.Context, text string, maxLength int) (*model.SummaryResponse, error) {
-			summary := "Summary of: " + text[:min(len(text), 50)] + "..."
-			if len(summary) > maxLength {
-				summary = summary[:maxLength]
+		SummarizeTextFunc: func(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+			summary := "Summary of: " + req.Text[:min(len(req.Text), 50)] + "..."
+			if len(summary) > req.MaxLength {
+				summary = summary[:req.MaxLength]
 			}
 			return &model.SummaryResponse{
-				OriginalText: text,
+				OriginalText: req.Text,
 				Summary:      summary,
-				Compression:  float64(len(summary)) / float64(len(text)),
+				Compression:  float64(len(summary)) / float64(len(req.Text)),
+				Model:        req.Model,
+				Strategy:     req.Strategy,
+				Chunks:       1,
+				Passes:       1,
+			}, nil
+		},
+		EmbedFunc: func(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+			data := make([]model.Embedding, len(req.Input))
+			tokens := 0
+			for i, text := range req.Input {
+				data[i] = model.Embedding{
+					Object:    "embedding",
+					Index:     i,
+					Embedding: []float64{float64(len(text)), 1, 0},
+				}
+				tokens += len(text) / 4
+			}
+			return &model.EmbeddingResponse{
+				Object: "list",
+				Data:   data,
+				Model:  req.Model,
+				Usage:  model.Usage{PromptTokens: tokens, TotalTokens: tokens},
+			}, nil
+		},
+		ClassifyZeroShotFunc: func(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+			labels := make([]model.LabelScore, len(req.Labels))
+			for i, label := range req.Labels {
+				labels[i] = model.LabelScore{Label: label, Score: 1 / float64(len(req.Labels))}
+			}
+			return &model.ClassificationResponse{
+				Text:       req.Text,
+				Model:      req.Model,
+				Labels:     labels,
+				MultiLabel: req.MultiLabel,
+			}, nil
+		},
+		ExtractEntitiesFunc: func(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+			return &model.EntityResponse{
+				Text:  req.Text,
+				Model: req.Model,
+				Entities: []model.Entity{
+					{Type: "MISC", Text: req.Text, Start: 0, End: len([]rune(req.Text)), Score: 0.9},
+				},
+			}, nil
+		},
+		TranslateFunc: func(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+			return &model.TranslationResponse{
+				OriginalText:   req.Text,
+				TranslatedText: "[" + req.TargetLang + "] " + req.Text,
+				SourceLang:     req.SourceLang,
+				TargetLang:     req.TargetLang,
+				Model:          req.Model,
+				Chunks:         1,
 			}, nil
 		},
-		ValidateModelFunc: func(modelName string) error {
+		AnswerQuestionFunc: func(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+			end := len([]rune(contextText))
+			if end > 10 {
+				end = 10
+			}
+			return &model.QAResponse{
+				Question: question,
+				Answer:   string([]rune(contextText)[:end]),
+				Start:    0,
+				End:      end,
+				Score:    0.9,
+				Model:    "mock-qa-model",
+				Windows:  1,
+			}, nil
+		},
+		ValidateModelFunc: func(ctx context.Context, modelName string) (*model.ModelInfo, error) {
 			if modelName == "" {
-				return fmt.Errorf("model name cannot be empty")
+				return nil, fmt.Errorf("model name cannot be empty")
 			}
 			if modelName == "invalid-model" {
-				return fmt.Errorf("model not supported")
+				return nil, fmt.Errorf("model not supported")
 			}
-			return nil
+			return &model.ModelInfo{
+				ID:          modelName,
+				Provider:    "huggingface",
+				PipelineTag: "text-generation",
+				Deployable:  true,
+				Source:      model.ModelSourceHub,
+			}, nil
 		},
 	}
 }
@@ -116,22 +229,58 @@ func (m *MockAIService) GenerateCompletion(ctx context.Context, req *model.AIReq
 	return m.GenerateCompletionFunc(ctx, req)
 }
 
+// GenerateTextStream implements model.AIService
+func (m *MockAIService) GenerateTextStream(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+	m.GenerateTextStreamCalls++
+	return m.GenerateTextStreamFunc(ctx, req, fn)
+}
+
 // AnalyzeSentiment implements model.AIService
-func (m *MockAIService) AnalyzeSentiment(ctx context.Context, text string) (*model.SentimentResponse, error) {
+func (m *MockAIService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
 	m.AnalyzeSentimentCalls++
-	return m.AnalyzeSentimentFunc(ctx, text)
+	return m.AnalyzeSentimentFunc(ctx, req)
 }
 
 // SummarizeText implements model.AIService
-func (m *MockAIService) SummarizeText(ctx context.Context, text string, maxLength int) (*model.SummaryResponse, error) {
+func (m *MockAIService) SummarizeText(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
 	m.SummarizeTextCalls++
-	return m.SummarizeTextFunc(ctx, text, maxLength)
+	return m.SummarizeTextFunc(ctx, req)
+}
+
+// Embed implements model.AIService
+func (m *MockAIService) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	m.EmbedCalls++
+	return m.EmbedFunc(ctx, req)
+}
+
+// ClassifyZeroShot implements model.AIService
+func (m *MockAIService) ClassifyZeroShot(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+	m.ClassifyZeroShotCalls++
+	return m.ClassifyZeroShotFunc(ctx, req)
+}
+
+// ExtractEntities implements model.AIService
+func (m *MockAIService) ExtractEntities(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+	m.ExtractEntitiesCalls++
+	return m.ExtractEntitiesFunc(ctx, req)
+}
+
+// Translate implements model.AIService
+func (m *MockAIService) Translate(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+	m.TranslateCalls++
+	return m.TranslateFunc(ctx, req)
+}
+
+// AnswerQuestion implements model.AIService
+func (m *MockAIService) AnswerQuestion(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
+	m.AnswerQuestionCalls++
+	return m.AnswerQuestionFunc(ctx, question, contextText)
 }
 
 // ValidateModel implements model.AIService
-func (m *MockAIService) ValidateModel(modelName string) error {
+func (m *MockAIService) ValidateModel(ctx context.Context, modelName string) (*model.ModelInfo, error) {
 	m.ValidateModelCalls++
-	return m.ValidateModelFunc(modelName)
+	return m.ValidateModelFunc(ctx, modelName)
 }
 
 // SetGenerateTextError makes GenerateText return an error
@@ -141,24 +290,66 @@ func (m *MockAIService) SetGenerateTextError(err error) {
 	}
 }
 
+// SetGenerateTextStreamError makes GenerateTextStream return an error
+func (m *MockAIService) SetGenerateTextStreamError(err error) {
+	m.GenerateTextStreamFunc = func(ctx context.Context, req *model.AIRequest, fn model.StreamFunc) (*model.AIResponse, error) {
+		return nil, err
+	}
+}
+
 // SetAnalyzeSentimentError makes AnalyzeSentiment return an error
 func (m *MockAIService) SetAnalyzeSentimentError(err error) {
-	m.AnalyzeSentimentFunc = func(ctx context.Context, text string) (*model.SentimentResponse, error) {
+	m.AnalyzeSentimentFunc = func(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
 		return nil, err
 	}
 }
 
 // SetSummarizeTextError makes SummarizeText return an error
 func (m *MockAIService) SetSummarizeTextError(err error) {
-	m.SummarizeTextFunc = func(ctx context.Context, text string, maxLength int) (*model.SummaryResponse, error) {
+	m.SummarizeTextFunc = func(ctx context.Context, req *model.SummaryRequest) (*model.SummaryResponse, error) {
+		return nil, err
+	}
+}
+
+// SetEmbedError makes Embed return an error
+func (m *MockAIService) SetEmbedError(err error) {
+	m.EmbedFunc = func(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+		return nil, err
+	}
+}
+
+// SetClassifyZeroShotError makes ClassifyZeroShot return an error
+func (m *MockAIService) SetClassifyZeroShotError(err error) {
+	m.ClassifyZeroShotFunc = func(ctx context.Context, req *model.ClassificationRequest) (*model.ClassificationResponse, error) {
+		return nil, err
+	}
+}
+
+// SetExtractEntitiesError makes ExtractEntities return an error
+func (m *MockAIService) SetExtractEntitiesError(err error) {
+	m.ExtractEntitiesFunc = func(ctx context.Context, req *model.EntityRequest) (*model.EntityResponse, error) {
+		return nil, err
+	}
+}
+
+// SetTranslateError makes Translate return an error
+func (m *MockAIService) SetTranslateError(err error) {
+	m.TranslateFunc = func(ctx context.Context, req *model.TranslationRequest) (*model.TranslationResponse, error) {
+		return nil, err
+	}
+}
+
+// SetAnswerQuestionError makes AnswerQuestion return an error
+func (m *MockAIService) SetAnswerQuestionError(err error) {
+	m.AnswerQuestionFunc = func(ctx context.Context, question, contextText string) (*model.QAResponse, error) {
 		return nil, err
 	}
 }
 
 // SetValidateModelError makes ValidateModel return an error
 func (m *MockAIService) SetValidateModelError(err error) {
-	m.ValidateModelFunc = func(modelName string) error {
-		return err
+	m.ValidateModelFunc = func(ctx context.Context, modelName string) (*model.ModelInfo, error) {
+		return nil, err
 	}
 }
 
@@ -166,8 +357,14 @@ func (m *MockAIService) SetValidateModelError(err error) {
 func (m *MockAIService) Reset() {
 	m.GenerateTextCalls = 0
 	m.GenerateCompletionCalls = 0
+	m.GenerateTextStreamCalls = 0
 	m.AnalyzeSentimentCalls = 0
 	m.SummarizeTextCalls = 0
+	m.EmbedCalls = 0
+	m.ClassifyZeroShotCalls = 0
+	m.ExtractEntitiesCalls = 0
+	m.TranslateCalls = 0
+	m.AnswerQuestionCalls = 0
 	m.ValidateModelCalls = 0
 }
 